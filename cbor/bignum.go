@@ -0,0 +1,161 @@
+// Copyright (C) 2022 The go-cbor Authors All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cbor
+
+import (
+	"math/big"
+	"reflect"
+)
+
+var (
+	bigIntType    = reflect.TypeFor[big.Int]()
+	bigIntPtrType = reflect.TypeFor[*big.Int]()
+	bigOne        = big.NewInt(1)
+)
+
+// encodeBigInt encodes the specified integer as an unsigned or negative bignum.
+// The integer is encoded as a basic integer if the preferred serialization is enabled and it fits.
+func (enc *Encoder) encodeBigInt(v *big.Int) error {
+	if v == nil {
+		return enc.encodePrimitiveTypes(nil)
+	}
+
+	// 3.4.3. Bignums.
+
+	n := new(big.Int).Set(v)
+	mt, tag := mtUint, tagPositiveBignum
+	if n.Sign() < 0 {
+		n.Sub(n.Neg(n), bigOne)
+		mt, tag = mtNInt, tagNegativeBignum
+	}
+	if enc.isShortestIntEnabled() && n.IsUint64() {
+		return writeShortestHeader(enc.writer, mt, n.Uint64())
+	}
+	if err := writeTagHeader(enc.writer, tag); err != nil {
+		return err
+	}
+	return enc.encodeByteString(n.Bytes())
+}
+
+// encodeInteger encodes the specified integer as a basic integer in the shortest form if it fits into int64, otherwise as a bignum.
+func (enc *Encoder) encodeInteger(v *big.Int) error {
+	if v.IsInt64() {
+		return writeShortestInt(enc.writer, v.Int64())
+	}
+	return enc.encodeBigInt(v)
+}
+
+// bigIntOf returns the integer of the specified bignum content. The negative bignum is -1 - n.
+func bigIntOf(tag tagNumber, v any) (*big.Int, error) {
+	b, ok := v.([]byte)
+	if !ok {
+		return nil, newErrorBignum(tag, v)
+	}
+	n := new(big.Int).SetBytes(b)
+	if tag == tagNegativeBignum {
+		n.Sub(n.Neg(n), bigOne)
+	}
+	return n, nil
+}
+
+// toBigInt returns the specified integer item as a big integer.
+func toBigInt(v any) (*big.Int, bool) {
+	switch n := v.(type) {
+	case *big.Int:
+		return n, n != nil
+	case int8:
+		return big.NewInt(int64(n)), true
+	case int16:
+		return big.NewInt(int64(n)), true
+	case int32:
+		return big.NewInt(int64(n)), true
+	case int64:
+		return big.NewInt(n), true
+	case uint8:
+		return new(big.Int).SetUint64(uint64(n)), true
+	case uint16:
+		return new(big.Int).SetUint64(uint64(n)), true
+	case uint32:
+		return new(big.Int).SetUint64(uint64(n)), true
+	case uint64:
+		return new(big.Int).SetUint64(n), true
+	}
+	return nil, false
+}
+
+// nolint: exhaustive
+// unmarshalBigIntTo stores the specified item to the specified destination if the item is a bignum or the destination is a big integer.
+// A bignum is stored to an integer or floating-point destination only if the value fits.
+func unmarshalBigIntTo(fromObj any, toVal reflect.Value) (bool, error) {
+	if !toVal.IsValid() {
+		return false, nil
+	}
+	for toVal.Kind() == reflect.Pointer && toVal.Type() != bigIntPtrType {
+		if toVal.IsNil() {
+			return false, nil
+		}
+		toVal = toVal.Elem()
+	}
+
+	switch toVal.Type() {
+	case bigIntPtrType, bigIntType:
+		n, ok := toBigInt(fromObj)
+		if !ok {
+			return false, nil
+		}
+		if toVal.Type() == bigIntType {
+			if !toVal.CanSet() {
+				return true, newErrorUnmarshalDataTypes(fromObj, toVal.Interface())
+			}
+			toVal.Set(reflect.ValueOf(*new(big.Int).Set(n)))
+			return true, nil
+		}
+		if toVal.IsNil() {
+			if !toVal.CanSet() {
+				return true, newErrorUnmarshalDataTypes(fromObj, toVal.Interface())
+			}
+			toVal.Set(reflect.New(bigIntType))
+		}
+		toVal.Interface().(*big.Int).Set(n) // nolint: forcetypeassert
+		return true, nil
+	}
+
+	n, ok := fromObj.(*big.Int)
+	if !ok || !toVal.CanSet() {
+		return false, nil
+	}
+	switch toVal.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !n.IsInt64() || toVal.OverflowInt(n.Int64()) {
+			return true, newErrorUnmarshalDataTypes(fromObj, toVal.Interface())
+		}
+		toVal.SetInt(n.Int64())
+		return true, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if !n.IsUint64() || toVal.OverflowUint(n.Uint64()) {
+			return true, newErrorUnmarshalDataTypes(fromObj, toVal.Interface())
+		}
+		toVal.SetUint(n.Uint64())
+		return true, nil
+	case reflect.Float32, reflect.Float64:
+		f, accuracy := new(big.Float).SetInt(n).Float64()
+		if accuracy != big.Exact || toVal.OverflowFloat(f) {
+			return true, newErrorUnmarshalDataTypes(fromObj, toVal.Interface())
+		}
+		toVal.SetFloat(f)
+		return true, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,42 @@
+// Copyright (C) 2022 The go-cbor Authors All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cbor
+
+import (
+	"reflect"
+)
+
+// ByteString represents a CBOR byte string as a comparable Go type.
+// Decoder::Decode() returns byte string map keys as ByteString because []byte cannot be used as a map key in Go.
+type ByteString string
+
+// Bytes returns the byte string as a byte slice.
+func (bs ByteString) Bytes() []byte {
+	return []byte(bs)
+}
+
+// mapKeyOf returns the specified decoded item as a comparable map key.
+func mapKeyOf(key any) (any, error) {
+	switch v := key.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return ByteString(v), nil
+	}
+	if !reflect.ValueOf(key).Comparable() {
+		return nil, newErrorIncomparableMapKey(key)
+	}
+	return key, nil
+}
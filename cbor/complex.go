@@ -0,0 +1,61 @@
+// Copyright (C) 2022 The go-cbor Authors All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cbor
+
+import (
+	"reflect"
+
+	"github.com/cybergarage/go-safecast/safecast"
+)
+
+// encodeComplex encodes the specified complex number as an array of the real and imaginary parts with the complex number tag.
+// The parts of complex64 are encoded as float32, and the parts of complex128 are encoded as float64.
+func (enc *Encoder) encodeComplex(item any) error {
+	if err := writeTagHeader(enc.writer, tagComplex); err != nil {
+		return err
+	}
+	if err := writeShortestHeader(enc.writer, mtArray, 2); err != nil {
+		return err
+	}
+	var re, im any
+	switch v := reflect.ValueOf(item); v.Kind() { // nolint: exhaustive
+	case reflect.Complex64:
+		c := v.Complex()
+		re, im = float32(real(c)), float32(imag(c))
+	default:
+		c := v.Complex()
+		re, im = real(c), imag(c)
+	}
+	if err := enc.Encode(re); err != nil {
+		return err
+	}
+	return enc.Encode(im)
+}
+
+// complexOf returns the complex number of the specified real and imaginary parts array.
+func complexOf(v any) (complex128, error) {
+	items, ok := v.([]any)
+	if !ok || len(items) != 2 {
+		return 0, newErrorComplex(v)
+	}
+	var re, im float64
+	if err := safecast.ToFloat64(items[0], &re); err != nil {
+		return 0, newErrorComplex(v)
+	}
+	if err := safecast.ToFloat64(items[1], &im); err != nil {
+		return 0, newErrorComplex(v)
+	}
+	return complex(re, im), nil
+}
@@ -14,15 +14,71 @@
 
 package cbor
 
+// MapSortMode represents an order of map keys when encoding maps.
+type MapSortMode int
+
+const (
+	// MapSortNone does not sort map keys.
+	MapSortNone MapSortMode = iota
+	// MapSortString sorts map keys by their string representations, which is the order of MapSortEnabled.
+	MapSortString
+	// MapSortBytewise sorts map keys by the bytewise lexicographic order of their encoded bytes as the core deterministic encoding of RFC 8949.
+	MapSortBytewise
+	// MapSortLengthFirst sorts map keys by the length of their encoded bytes first and then bytewise as the canonical CBOR of RFC 7049 and CTAP2.
+	MapSortLengthFirst
+)
+
+// TimeMode represents an encoding format of time.Time.
+type TimeMode int
+
+const (
+	// TimeRFC3339 encodes time.Time as a RFC 3339 string with the standard date/time tag 0.
+	TimeRFC3339 TimeMode = iota
+	// TimeUnix encodes time.Time as integer seconds with the epoch-based date/time tag 1.
+	TimeUnix
+	// TimeRFC3339Nano encodes time.Time as a RFC 3339 string with nanoseconds and the standard date/time tag 0.
+	TimeRFC3339Nano
+	// TimeUnixFloat encodes time.Time as floating-point seconds with the epoch-based date/time tag 1.
+	TimeUnixFloat
+	// TimeExtended encodes time.Time as a map with integer seconds, nanoseconds, and a time zone hint with the extended time tag 1001 of RFC 9581.
+	TimeExtended
+)
+
+// UndefinedMode represents a way to unmarshal the undefined simple value.
+type UndefinedMode int
+
+const (
+	// UndefinedZero stores the zero value of the destination type.
+	UndefinedZero UndefinedMode = iota
+	// UndefinedError returns an error unless the destination type is Undefined.
+	UndefinedError
+)
+
 // Config represents a configuration for CBOR encoder and decoder.
 type Config struct {
-	MapSortEnabled bool
+	MapSortEnabled       bool
+	MapSortMode          MapSortMode
+	ShortestIntEnabled   bool
+	ShortestFloatEnabled bool
+	NaNCanonicalEnabled  bool
+	DeterministicEnabled bool
+	TimeMode             TimeMode
+	TagSet               *TagSet
+	UndefinedMode        UndefinedMode
 }
 
 // NewConfig returns a new config instance.
 func NewConfig() *Config {
 	return &Config{
-		MapSortEnabled: false,
+		MapSortEnabled:       false,
+		MapSortMode:          MapSortNone,
+		ShortestIntEnabled:   false,
+		ShortestFloatEnabled: false,
+		NaNCanonicalEnabled:  false,
+		DeterministicEnabled: false,
+		TimeMode:             TimeRFC3339,
+		TagSet:               nil,
+		UndefinedMode:        UndefinedZero,
 	}
 }
 
@@ -35,3 +91,107 @@ func (config *Config) SetMapSortEnabled(flag bool) {
 func (config *Config) IsMapSortEnabled() bool {
 	return config.MapSortEnabled
 }
+
+// SetMapSortMode sets an order of map keys. The specified order takes precedence over MapSortEnabled.
+func (config *Config) SetMapSortMode(mode MapSortMode) {
+	config.MapSortMode = mode
+}
+
+// GetMapSortMode returns the order of map keys.
+func (config *Config) GetMapSortMode() MapSortMode {
+	return config.MapSortMode
+}
+
+// SetShortestIntEnabled sets a flag to encode integers in the shortest form as the preferred serialization of RFC 8949 instead of the width of the Go data type.
+func (config *Config) SetShortestIntEnabled(flag bool) {
+	config.ShortestIntEnabled = flag
+}
+
+// IsShortestIntEnabled returns true whether integers are encoded in the shortest form.
+func (config *Config) IsShortestIntEnabled() bool {
+	return config.ShortestIntEnabled
+}
+
+// SetShortestFloatEnabled sets a flag to encode floating-point numbers in the shortest format which preserves the value.
+func (config *Config) SetShortestFloatEnabled(flag bool) {
+	config.ShortestFloatEnabled = flag
+}
+
+// IsShortestFloatEnabled returns true whether floating-point numbers are encoded in the shortest format.
+func (config *Config) IsShortestFloatEnabled() bool {
+	return config.ShortestFloatEnabled
+}
+
+// SetNaNCanonicalEnabled sets a flag to encode any NaN as the canonical quiet NaN instead of preserving the payload.
+func (config *Config) SetNaNCanonicalEnabled(flag bool) {
+	config.NaNCanonicalEnabled = flag
+}
+
+// IsNaNCanonicalEnabled returns true whether any NaN is encoded as the canonical quiet NaN.
+func (config *Config) IsNaNCanonicalEnabled() bool {
+	return config.NaNCanonicalEnabled
+}
+
+// SetDeterministicEnabled sets a flag to encode items as the core deterministic encoding of RFC 8949.
+// The deterministic encoding uses the shortest form of integers and floating-point numbers, sorts map keys bytewise unless another order is specified by SetMapSortMode(), and does not allow indefinite-length items.
+func (config *Config) SetDeterministicEnabled(flag bool) {
+	config.DeterministicEnabled = flag
+}
+
+// IsDeterministicEnabled returns true whether items are encoded as the core deterministic encoding.
+func (config *Config) IsDeterministicEnabled() bool {
+	return config.DeterministicEnabled
+}
+
+// SetTimeMode sets an encoding format of time.Time.
+func (config *Config) SetTimeMode(mode TimeMode) {
+	config.TimeMode = mode
+}
+
+// GetTimeMode returns the encoding format of time.Time.
+func (config *Config) GetTimeMode() TimeMode {
+	return config.TimeMode
+}
+
+// SetTagSet sets a set of user-defined tags which are encoded and decoded with the registered types.
+func (config *Config) SetTagSet(ts *TagSet) {
+	config.TagSet = ts
+}
+
+// GetTagSet returns the set of user-defined tags.
+func (config *Config) GetTagSet() *TagSet {
+	return config.TagSet
+}
+
+// SetUndefinedMode sets a way to unmarshal the undefined simple value.
+func (config *Config) SetUndefinedMode(mode UndefinedMode) {
+	config.UndefinedMode = mode
+}
+
+// GetUndefinedMode returns the way to unmarshal the undefined simple value.
+func (config *Config) GetUndefinedMode() UndefinedMode {
+	return config.UndefinedMode
+}
+
+// mapSortMode returns the effective order of map keys.
+func (config *Config) mapSortMode() MapSortMode {
+	switch {
+	case config.DeterministicEnabled && (config.MapSortMode == MapSortNone || config.MapSortMode == MapSortString):
+		return MapSortBytewise
+	case config.MapSortMode != MapSortNone:
+		return config.MapSortMode
+	case config.MapSortEnabled:
+		return MapSortString
+	}
+	return MapSortNone
+}
+
+// isShortestIntEnabled returns true whether integers are effectively encoded in the shortest form.
+func (config *Config) isShortestIntEnabled() bool {
+	return config.ShortestIntEnabled || config.DeterministicEnabled
+}
+
+// isShortestFloatEnabled returns true whether floating-point numbers are effectively encoded in the shortest format.
+func (config *Config) isShortestFloatEnabled() bool {
+	return config.ShortestFloatEnabled || config.DeterministicEnabled
+}
@@ -17,6 +17,8 @@ package cbor
 const (
 	majorTypeMask = 0xE0
 	majorInfoMask = 0x1F
+	// 3.2.1. The "break" Stop Code.
+	breakCode = 0xFF
 )
 
 type majorType byte
@@ -41,6 +43,8 @@ const (
 	aiTwoByte   majorInfo = 25
 	aiFourByte  majorInfo = 26
 	aiEightByte majorInfo = 27
+	// 3.2. Indefinite Lengths for Some Major Types.
+	aiIndefinite majorInfo = 31
 	// 3.3. Floating-Point Numbers and Values with No Content.
 	fpnFloat16 majorInfo = 25
 	fpnFloat32 majorInfo = 26
@@ -48,7 +52,34 @@ const (
 	simpFalse  majorInfo = 20
 	simpTrue   majorInfo = 21
 	simpNull   majorInfo = 22
+	simpUndef  majorInfo = 23
+	// 3.3. The simple values less than 32 are not well-formed in the extension byte.
+	simpMinOneByte = 32
+)
+
+type tagNumber uint64
+
+const (
 	// 3.4. Tagging of Items.
-	tagStdDateTime   majorInfo = 0
-	tagEpochDateTime majorInfo = 1
+	tagStdDateTime   tagNumber = 0
+	tagEpochDateTime tagNumber = 1
+	// 3.4.3. Bignums.
+	tagPositiveBignum tagNumber = 2
+	tagNegativeBignum tagNumber = 3
+	// 3.4.4. Decimal Fractions and Bigfloats.
+	tagDecimalFraction tagNumber = 4
+	tagBigFloat        tagNumber = 5
+	// IANA CBOR Tags: Rational number.
+	tagRational tagNumber = 30
+	// RFC 9581: Concise Binary Object Representation (CBOR) Tags for Time, Duration, and Period.
+	tagExtendedTime tagNumber = 1001
+	// IANA CBOR Tags: Complex number.
+	tagComplex tagNumber = 43000
+)
+
+const (
+	// 4.2.2. Additional Deterministic Encoding Considerations.
+	canonicalNaN16 uint16 = 0x7E00
+	canonicalNaN32 uint32 = 0x7FC00000
+	canonicalNaN64 uint64 = 0x7FF8000000000000
 )
@@ -15,9 +15,15 @@
 package cbor
 
 import (
+	"bytes"
+	"errors"
 	"io"
 	"math"
+	"math/big"
+	"reflect"
 	"time"
+
+	"github.com/cybergarage/go-safecast/safecast"
 )
 
 // An Decoder reads CBOR values from an output stream.
@@ -37,9 +43,41 @@ func NewDecoder(r io.Reader) *Decoder {
 	}
 }
 
-// nolint: gocyclo, maintidx, exhaustive
 // Decode returns a next decoded item from the specified reader if available, otherwise returns EOF or another error.
 func (dec *Decoder) Decode() (any, error) {
+	return dec.decode(nil)
+}
+
+// decodeRaw returns the encoded bytes of a next item as a raw item without converting it.
+func (dec *Decoder) decodeRaw() (rawItem, error) {
+	var buf bytes.Buffer
+	reader := dec.reader
+	dec.reader = io.TeeReader(reader, &buf)
+	_, err := dec.decodeOrBreak(nil)
+	dec.reader = reader
+	if err != nil {
+		return nil, err
+	}
+	return rawItem(buf.Bytes()), nil
+}
+
+// decode returns a next decoded item. The specified type is a hint of the destination type for Unmarshal(),
+// and the items whose destination type requires the encoded bytes are returned as raw items.
+func (dec *Decoder) decode(t reflect.Type) (any, error) {
+	item, err := dec.decodeOrBreak(t)
+	if errors.Is(err, errBreak) {
+		return nil, newErrorNotSupportedAddInfo(mtFloat, aiIndefinite)
+	}
+	return item, err
+}
+
+// nolint: gocyclo, maintidx, exhaustive
+// decodeOrBreak returns a next decoded item, or returns errBreak if the next item is the break stop code.
+func (dec *Decoder) decodeOrBreak(t reflect.Type) (any, error) {
+	if isRawItemType(t) {
+		return dec.decodeRaw()
+	}
+
 	returnDecordedUint8 := func(v uint8) any {
 		if math.MaxInt8 < v {
 			return v
@@ -68,9 +106,39 @@ func (dec *Decoder) Decode() (any, error) {
 		return int64(v)
 	}
 
-	readNumberOfItems := func(mt majorType, ai majorInfo) (int, error) {
+	returnDecordedNint8 := func(v uint8) any {
+		if math.MaxInt8 < v {
+			return -int16(v) - 1
+		}
+		return -int8(v) - 1
+	}
+
+	returnDecordedNint16 := func(v uint16) any {
+		if math.MaxInt16 < v {
+			return -int32(v) - 1
+		}
+		return -int16(v) - 1
+	}
+
+	returnDecordedNint32 := func(v uint32) any {
+		if math.MaxInt32 < v {
+			return -int64(v) - 1
+		}
+		return -int32(v) - 1
+	}
+
+	returnDecordedNint64 := func(v uint64) any {
+		if math.MaxInt64 < v {
+			// The negative integer less than math.MinInt64 is returned as a big integer without wraparound.
+			n := new(big.Int).SetUint64(v)
+			return n.Sub(n.Neg(n), bigOne)
+		}
+		return -int64(v) - 1
+	}
+
+	readArgument := func(mt majorType, ai majorInfo) (uint64, error) {
 		if ai < aiOneByte {
-			return int(ai), nil
+			return uint64(ai), nil
 		}
 		switch ai {
 		case aiOneByte:
@@ -78,30 +146,69 @@ func (dec *Decoder) Decode() (any, error) {
 			if err != nil {
 				return 0, err
 			}
-			return int(v), nil
+			return uint64(v), nil
 		case aiTwoByte:
 			v, err := readUint16Bytes(dec.reader)
 			if err != nil {
 				return 0, err
 			}
-			return int(v), nil
+			return uint64(v), nil
 		case aiFourByte:
 			v, err := readUint32Bytes(dec.reader)
 			if err != nil {
 				return 0, err
 			}
-			return int(v), nil
+			return uint64(v), nil
 		case aiEightByte:
-			v, err := readUint64Bytes(dec.reader)
+			return readUint64Bytes(dec.reader)
+		}
+		return 0, newErrorNotSupportedAddInfo(mt, ai)
+	}
+
+	readNumberOfItems := func(mt majorType, ai majorInfo) (int, error) {
+		v, err := readArgument(mt, ai)
+		if err != nil {
+			return 0, err
+		}
+		var n int
+		if err := safecast.ToInt(v, &n); err != nil {
+			return 0, newErrorNumberOfItemsOverflow(mt, v)
+		}
+		return n, nil
+	}
+
+	readIndefiniteByteString := func(m majorType) ([]byte, error) {
+		// 3.2.3. Indefinite-Length Byte Strings and Text Strings.
+		chunks := []byte{}
+		chunkHeader := make([]byte, 1)
+		for {
+			if _, err := io.ReadFull(dec.reader, chunkHeader); err != nil {
+				return nil, err
+			}
+			if chunkHeader[0] == breakCode {
+				return chunks, nil
+			}
+			chunkType := majorType(chunkHeader[0] & majorTypeMask)
+			chunkInfo := majorInfo(chunkHeader[0] & majorInfoMask)
+			if chunkType != m || chunkInfo == aiIndefinite {
+				return nil, newErrorIndefiniteChunk(m, chunkType, chunkInfo)
+			}
+			n, err := readNumberOfItems(chunkType, chunkInfo)
 			if err != nil {
-				return 0, err
+				return nil, err
 			}
-			return int(v), nil
+			chunk, err := readBytes(dec.reader, n)
+			if err != nil {
+				return nil, err
+			}
+			chunks = append(chunks, chunk...)
 		}
-		return 0, newErrorNotSupportedAddInfo(mt, ai)
 	}
 
 	readByteString := func(m majorType, i majorInfo) ([]byte, error) {
+		if i == aiIndefinite {
+			return readIndefiniteByteString(m)
+		}
 		n, err := readNumberOfItems(m, i)
 		if err != nil {
 			return nil, err
@@ -160,17 +267,33 @@ func (dec *Decoder) Decode() (any, error) {
 		return nil, newErrorNotSupportedAddInfo(mtUint, majorInfo)
 	case mtNInt:
 		if majorInfo < aiOneByte {
-			return -int8(majorInfo + 1), nil
+			return -int8(majorInfo) - 1, nil
 		}
 		switch majorInfo {
 		case aiOneByte:
-			return readNint8Bytes(dec.reader)
+			v, err := readUint8Bytes(dec.reader)
+			if err != nil {
+				return nil, err
+			}
+			return returnDecordedNint8(v), nil
 		case aiTwoByte:
-			return readNint16Bytes(dec.reader)
+			v, err := readUint16Bytes(dec.reader)
+			if err != nil {
+				return nil, err
+			}
+			return returnDecordedNint16(v), nil
 		case aiFourByte:
-			return readNint32Bytes(dec.reader)
+			v, err := readUint32Bytes(dec.reader)
+			if err != nil {
+				return nil, err
+			}
+			return returnDecordedNint32(v), nil
 		case aiEightByte:
-			return readNint64Bytes(dec.reader)
+			v, err := readUint64Bytes(dec.reader)
+			if err != nil {
+				return nil, err
+			}
+			return returnDecordedNint64(v), nil
 		}
 		return nil, newErrorNotSupportedAddInfo(mtNInt, majorInfo)
 	case mtBytes:
@@ -178,13 +301,26 @@ func (dec *Decoder) Decode() (any, error) {
 	case mtText:
 		return readTextString(mtText, majorInfo)
 	case mtArray:
+		itemArray := make([]any, 0)
+		if majorInfo == aiIndefinite {
+			// 3.2.2. Indefinite-Length Arrays and Maps.
+			for {
+				item, err := dec.decodeOrBreak(arrayElemTypeOf(t, len(itemArray)))
+				if errors.Is(err, errBreak) {
+					return itemArray, nil
+				}
+				if err != nil {
+					return nil, err
+				}
+				itemArray = append(itemArray, item)
+			}
+		}
 		itemCount, err := readNumberOfItems(mtArray, majorInfo)
 		if err != nil {
 			return nil, err
 		}
-		itemArray := make([]any, 0)
-		for range itemCount {
-			item, err := dec.Decode()
+		for n := range itemCount {
+			item, err := dec.decode(arrayElemTypeOf(t, n))
 			if err != nil {
 				return nil, err
 			}
@@ -192,25 +328,68 @@ func (dec *Decoder) Decode() (any, error) {
 		}
 		return itemArray, nil
 	case mtMap:
-		itemArray, err := readNumberOfItems(mtArray, majorInfo)
+		itemMap := map[any]any{}
+		decodeMapItem := func(key any) error {
+			key, err := mapKeyOf(key)
+			if err != nil {
+				return err
+			}
+			val, err := dec.decode(mapElemTypeOf(t, key))
+			if err != nil {
+				return err
+			}
+			itemMap[key] = val
+			return nil
+		}
+		if majorInfo == aiIndefinite {
+			// 3.2.2. Indefinite-Length Arrays and Maps.
+			for {
+				key, err := dec.decodeOrBreak(nil)
+				if errors.Is(err, errBreak) {
+					return itemMap, nil
+				}
+				if err != nil {
+					return nil, err
+				}
+				if err := decodeMapItem(key); err != nil {
+					return nil, err
+				}
+			}
+		}
+		itemCount, err := readNumberOfItems(mtMap, majorInfo)
 		if err != nil {
 			return nil, err
 		}
-		itemMap := map[any]any{}
-		for range itemArray {
+		for range itemCount {
 			key, err := dec.Decode()
 			if err != nil {
 				return nil, err
 			}
-			val, err := dec.Decode()
-			if err != nil {
+			if err := decodeMapItem(key); err != nil {
 				return nil, err
 			}
-			itemMap[key] = val
 		}
 		return itemMap, nil
 	case mtTag:
-		switch majorInfo {
+		tag, err := readArgument(mtTag, majorInfo)
+		if err != nil {
+			return nil, err
+		}
+		if indirectTypeOf(t) == rawTagType {
+			content, err := dec.decodeRaw()
+			if err != nil {
+				return nil, err
+			}
+			return RawTag{Number: tag, Content: content}, nil
+		}
+		if entry, ok := dec.TagSet.entryByNumber(tagNumber(tag)); ok {
+			content, err := dec.Decode()
+			if err != nil {
+				return nil, err
+			}
+			return dec.decodeRegisteredTag(entry, content)
+		}
+		switch tagNumber(tag) {
 		case tagStdDateTime:
 			dateTime, err := dec.Decode()
 			if err != nil {
@@ -222,8 +401,54 @@ func (dec *Decoder) Decode() (any, error) {
 			}
 			return time.Parse(time.RFC3339, dateTimeStr)
 		case tagEpochDateTime:
+			epochTime, err := dec.Decode()
+			if err != nil {
+				return nil, err
+			}
+			return epochTimeOf(epochTime)
+		case tagPositiveBignum, tagNegativeBignum:
+			bignum, err := dec.Decode()
+			if err != nil {
+				return nil, err
+			}
+			return bigIntOf(tagNumber(tag), bignum)
+		case tagDecimalFraction, tagBigFloat:
+			fraction, err := dec.Decode()
+			if err != nil {
+				return nil, err
+			}
+			exp, mant, err := fractionOf(tagNumber(tag), fraction)
+			if err != nil {
+				return nil, err
+			}
+			if tagNumber(tag) == tagDecimalFraction {
+				return DecimalFraction{Exponent: exp, Mantissa: mant}, nil
+			}
+			return BigFloat{Exponent: exp, Mantissa: mant}, nil
+		case tagRational:
+			rational, err := dec.Decode()
+			if err != nil {
+				return nil, err
+			}
+			return ratOf(rational)
+		case tagComplex:
+			parts, err := dec.Decode()
+			if err != nil {
+				return nil, err
+			}
+			return complexOf(parts)
+		case tagExtendedTime:
+			extendedTime, err := dec.Decode()
+			if err != nil {
+				return nil, err
+			}
+			return extendedTimeOf(extendedTime)
 		}
-		return nil, newErrorNotSupportedMajorType(majorType)
+		content, err := dec.Decode()
+		if err != nil {
+			return nil, err
+		}
+		return Tag{Number: tag, Content: content}, nil
 	case mtFloat:
 		switch majorInfo {
 		case simpFalse:
@@ -232,15 +457,89 @@ func (dec *Decoder) Decode() (any, error) {
 			return true, nil
 		case simpNull:
 			return nil, nil
+		case simpUndef:
+			return Undefined{}, nil
+		case aiOneByte:
+			v, err := readUint8Bytes(dec.reader)
+			if err != nil {
+				return nil, err
+			}
+			if v < simpMinOneByte {
+				return nil, newErrorSimpleValue(v)
+			}
+			return SimpleValue(v), nil
 		case fpnFloat16:
-			return nil, newErrorNotSupportedAddInfo(mtFloat, majorInfo)
+			v, err := readFloat16Bytes(dec.reader)
+			if err != nil {
+				return nil, err
+			}
+			if v != v {
+				return math.Float64frombits(float32NaNToFloat64Bits(math.Float32bits(v))), nil
+			}
+			return float64(v), nil
 		case fpnFloat32:
 			return readFloat32Bytes(dec.reader)
 		case fpnFloat64:
 			return readFloat64Bytes(dec.reader)
+		case aiIndefinite:
+			return nil, errBreak
+		}
+		if majorInfo < simpFalse {
+			return SimpleValue(majorInfo), nil
 		}
 		return nil, newErrorNotSupportedAddInfo(mtFloat, majorInfo)
 	}
 
 	return nil, newErrorNotSupportedMajorType(majorType)
 }
+
+// nolint: exhaustive
+// arrayElemTypeOf returns the element type for the specified index of the specified array, slice, or toarray struct type if available, otherwise returns nil.
+func arrayElemTypeOf(t reflect.Type, n int) reflect.Type {
+	t = indirectTypeOf(t)
+	if t == nil {
+		return nil
+	}
+	switch t.Kind() {
+	case reflect.Array, reflect.Slice:
+		return t.Elem()
+	case reflect.Struct:
+		if !isStructToArray(t) {
+			return nil
+		}
+		fields := structFieldsOf(t)
+		if len(fields) <= n {
+			return nil
+		}
+		return fields[n].typ
+	}
+	return nil
+}
+
+// nolint: exhaustive
+// mapElemTypeOf returns the value type for the specified key of the specified map or struct type if available, otherwise returns nil.
+func mapElemTypeOf(t reflect.Type, key any) reflect.Type {
+	t = indirectTypeOf(t)
+	if t == nil {
+		return nil
+	}
+	switch t.Kind() {
+	case reflect.Map:
+		return t.Elem()
+	case reflect.Struct:
+		field, ok := structFieldByKey(t, key)
+		if !ok {
+			return nil
+		}
+		return field.typ
+	}
+	return nil
+}
+
+// indirectTypeOf returns the type that the specified pointer type points to.
+func indirectTypeOf(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
@@ -15,9 +15,12 @@
 package cbor
 
 import (
+	"bytes"
+	"encoding"
 	"fmt"
 	"io"
 	"math"
+	"math/big"
 	"reflect"
 	"sort"
 	"time"
@@ -27,27 +30,151 @@ import (
 type Encoder struct {
 	*Config
 
-	writer io.Writer
+	writer      io.Writer
+	indefinites []majorType
 }
 
 // NewEncoder returns a new encoder that writes to the specified writer.
 func NewEncoder(w io.Writer) *Encoder {
 	return &Encoder{
-		Config: NewConfig(),
-		writer: w,
+		Config:      NewConfig(),
+		writer:      w,
+		indefinites: []majorType{},
 	}
 }
 
+// BeginArray writes the header of an indefinite-length array. The items encoded until End() is called are the elements of the array.
+func (enc *Encoder) BeginArray() error {
+	return enc.beginIndefinite(mtArray)
+}
+
+// BeginMap writes the header of an indefinite-length map. The items encoded until End() is called are the alternate keys and values of the map.
+func (enc *Encoder) BeginMap() error {
+	return enc.beginIndefinite(mtMap)
+}
+
+// BeginBytes writes the header of an indefinite-length byte string. The byte strings encoded until End() is called are the chunks of the byte string.
+func (enc *Encoder) BeginBytes() error {
+	return enc.beginIndefinite(mtBytes)
+}
+
+// BeginText writes the header of an indefinite-length text string. The text strings encoded until End() is called are the chunks of the text string.
+func (enc *Encoder) BeginText() error {
+	return enc.beginIndefinite(mtText)
+}
+
+// End writes the break stop code to close the indefinite-length item which is begun last.
+func (enc *Encoder) End() error {
+	n := len(enc.indefinites)
+	if n == 0 {
+		return newErrorIndefiniteNotBegun()
+	}
+	enc.indefinites = enc.indefinites[:n-1]
+	return writeByte(enc.writer, breakCode)
+}
+
+func (enc *Encoder) beginIndefinite(mt majorType) error {
+	if enc.DeterministicEnabled {
+		return newErrorDeterministicIndefinite()
+	}
+	if stringType, ok := enc.indefiniteStringType(); ok {
+		return newErrorIndefiniteChunkType(stringType, "indefinite-length item")
+	}
+	if err := writeHeader(enc.writer, mt, aiIndefinite); err != nil {
+		return err
+	}
+	enc.indefinites = append(enc.indefinites, mt)
+	return nil
+}
+
+// indefiniteStringType returns the major type of the indefinite-length string if the indefinite-length item begun last is a string.
+func (enc *Encoder) indefiniteStringType() (majorType, bool) {
+	n := len(enc.indefinites)
+	if n == 0 {
+		return 0, false
+	}
+	switch mt := enc.indefinites[n-1]; mt {
+	case mtBytes, mtText:
+		return mt, true
+	}
+	return 0, false
+}
+
 // Encode writes the specified object to the specified writer.
 func (enc *Encoder) Encode(item any) error {
+	// 3.2.3. Indefinite-Length Byte Strings and Text Strings.
+	if stringType, ok := enc.indefiniteStringType(); ok {
+		if !isChunkOf(stringType, item) {
+			return newErrorIndefiniteChunkType(stringType, item)
+		}
+	}
+
+	// User-defined tags which are registered with Go types
+	if ok, err := enc.encodeRegisteredTag(item); ok {
+		return err
+	}
+
+	return enc.encodeUntagged(item)
+}
+
+// encodeUntagged writes the specified object without the user-defined tags.
+func (enc *Encoder) encodeUntagged(item any) error {
+	// Nil pointers are encoded as null without calling their marshalers.
+	if v := reflect.ValueOf(item); v.Kind() == reflect.Pointer && v.IsNil() {
+		return enc.encodePrimitiveTypes(nil)
+	}
+
+	// User-defined data types which marshal themselves
+	if v, ok := item.(Marshaler); ok {
+		return enc.encodeMarshaler(v)
+	}
+
 	// Special data types that cannot be determined by reflect package
-	switch item.(type) {
+	switch v := item.(type) {
 	case []byte: // Recognize as a byte array instead of a uint8 array。
 		return enc.encodePrimitiveTypes(item)
+	case ByteString:
+		return enc.encodeByteString(v.Bytes())
 	case time.Time:
 		return enc.encodeStdStruct(item)
+	case *time.Time:
+		return enc.encodeStdStruct(*v)
 	case nil:
 		return enc.encodePrimitiveTypes(item)
+	case big.Int:
+		return enc.encodeBigInt(&v)
+	case *big.Int:
+		return enc.encodeBigInt(v)
+	case big.Rat:
+		return enc.encodeRat(&v)
+	case *big.Rat:
+		return enc.encodeRat(v)
+	case SimpleValue:
+		return enc.encodeSimpleValue(v)
+	case Undefined:
+		return writeHeader(enc.writer, mtFloat, simpUndef)
+	case Tag:
+		return enc.encodeTag(v.Number, v.Content)
+	case *Tag:
+		if v == nil {
+			return enc.encodePrimitiveTypes(nil)
+		}
+		return enc.encodeTag(v.Number, v.Content)
+	case RawTag:
+		return enc.encodeRawTag(v.Number, v.Content)
+	case *RawTag:
+		if v == nil {
+			return enc.encodePrimitiveTypes(nil)
+		}
+		return enc.encodeRawTag(v.Number, v.Content)
+	case DecimalFraction:
+		return enc.encodeFraction(tagDecimalFraction, v.Exponent, v.Mantissa)
+	case BigFloat:
+		return enc.encodeFraction(tagBigFloat, v.Exponent, v.Mantissa)
+	case encoding.BinaryMarshaler:
+		return enc.encodeBinaryMarshaler(v)
+	case encoding.TextMarshaler:
+		return enc.encodeTextMarshaler(v)
 	}
 
 	switch reflect.TypeOf(item).Kind() {
@@ -57,8 +184,11 @@ func (enc *Encoder) Encode(item any) error {
 	// Major type 4: An array of data items.
 	case reflect.Array, reflect.Slice:
 		return enc.encodeArray(item)
-	case reflect.Struct, reflect.Pointer:
+	case reflect.Struct:
 		return enc.encodeStruct(item)
+	// Pointers are encoded as their pointees.
+	case reflect.Pointer:
+		return enc.Encode(reflect.ValueOf(item).Elem().Interface())
 	// 3. Specification of the CBOR Encoding.
 	case reflect.Bool,
 		reflect.Int,
@@ -77,6 +207,7 @@ func (enc *Encoder) Encode(item any) error {
 		return enc.encodePrimitiveTypes(item)
 	case reflect.Complex64,
 		reflect.Complex128:
+		return enc.encodeComplex(item)
 	case reflect.Invalid,
 		reflect.Chan,
 		reflect.Func,
@@ -89,36 +220,57 @@ func (enc *Encoder) Encode(item any) error {
 	return newErrorNotSupportedNativeType(item)
 }
 
-func (enc *Encoder) encodeNumberOfBytes(mt majorType, n int) error {
-	header := byte(mt)
-	switch {
-	case n < int(aiOneByte):
-		header |= uint8(n)
-	case n < math.MaxUint8:
-		header |= byte(aiOneByte)
-	case n < math.MaxUint16:
-		header |= byte(aiTwoByte)
-	case n < math.MaxUint32:
-		header |= byte(aiFourByte)
-	default:
-		header |= byte(aiEightByte)
+// isChunkOf returns true if the specified item is a definite-length string of the specified major type.
+func isChunkOf(mt majorType, item any) bool {
+	switch item.(type) {
+	case []byte, ByteString:
+		return mt == mtBytes
+	case string:
+		return mt == mtText
 	}
-	if err := writeByte(enc.writer, header); err != nil {
+	return false
+}
+
+func (enc *Encoder) encodeMarshaler(v Marshaler) error {
+	if isNilPointer(v) {
+		return enc.encodePrimitiveTypes(nil)
+	}
+	b, err := v.MarshalCBOR()
+	if err != nil {
 		return err
 	}
+	// The encoded bytes must be a single well-formed data item not to corrupt the enclosing items.
+	r := bytes.NewReader(b)
+	if _, err := NewDecoder(r).decodeRaw(); err != nil || r.Len() != 0 {
+		return newErrorMarshalerItem(v, b)
+	}
+	return writeBytes(enc.writer, b)
+}
 
-	switch {
-	case n < int(aiOneByte):
-		return nil
-	case n < math.MaxUint8:
-		return writeUint8Bytes(enc.writer, uint8(n))
-	case n < math.MaxUint16:
-		return writeUint16Bytes(enc.writer, uint16(n))
-	case n < math.MaxUint32:
-		return writeUint32Bytes(enc.writer, uint32(n))
-	default:
-		return writeUint64Bytes(enc.writer, uint64(n))
+func (enc *Encoder) encodeBinaryMarshaler(v encoding.BinaryMarshaler) error {
+	if isNilPointer(v) {
+		return enc.encodePrimitiveTypes(nil)
+	}
+	b, err := v.MarshalBinary()
+	if err != nil {
+		return err
 	}
+	return enc.encodeByteString(b)
+}
+
+func (enc *Encoder) encodeTextMarshaler(v encoding.TextMarshaler) error {
+	if isNilPointer(v) {
+		return enc.encodePrimitiveTypes(nil)
+	}
+	b, err := v.MarshalText()
+	if err != nil {
+		return err
+	}
+	return enc.encodeTextString(string(b))
+}
+
+func (enc *Encoder) encodeNumberOfBytes(mt majorType, n int) error {
+	return writeShortestHeader(enc.writer, mt, uint64(n))
 }
 
 func (enc *Encoder) encodeTextString(v string) error {
@@ -187,6 +339,75 @@ func (enc *Encoder) encodePrimitiveTypes(item any) error {
 		return writeUint64Bytes(enc.writer, v)
 	}
 
+	// encodeFloat writes the specified non-NaN value, which is converted between the formats without losing precision.
+	encodeFloat := func(v float64, fpn majorInfo) error {
+		if enc.isShortestFloatEnabled() {
+			fpn = shortestFloatInfo(v)
+		}
+		if err := writeHeader(enc.writer, mtFloat, fpn); err != nil {
+			return err
+		}
+		switch fpn {
+		case fpnFloat16:
+			return writeFloat16Bytes(enc.writer, float32(v))
+		case fpnFloat32:
+			return writeFloat32Bytes(enc.writer, float32(v))
+		default:
+			return writeFloat64Bytes(enc.writer, v)
+		}
+	}
+
+	// encodeNaN writes the specified NaN in float64 bits, which is converted from the specified format, to keep the payload.
+	encodeNaN := func(bits uint64, fpn majorInfo) error {
+		if enc.NaNCanonicalEnabled {
+			if enc.isShortestFloatEnabled() {
+				fpn = fpnFloat16
+			}
+			return writeCanonicalNaN(enc.writer, fpn)
+		}
+		if enc.isShortestFloatEnabled() {
+			fpn = shortestNaNInfo(bits)
+		}
+		if err := writeHeader(enc.writer, mtFloat, fpn); err != nil {
+			return err
+		}
+		switch fpn {
+		case fpnFloat16:
+			return writeUint16Bytes(enc.writer, float64NaNToFloat16Bits(bits))
+		case fpnFloat32:
+			return writeUint32Bytes(enc.writer, float64NaNToFloat32Bits(bits))
+		default:
+			return writeUint64Bytes(enc.writer, bits)
+		}
+	}
+
+	// 4.1. Preferred Serialization.
+
+	if enc.isShortestIntEnabled() {
+		switch v := item.(type) {
+		case uint8:
+			return writeShortestHeader(enc.writer, mtUint, uint64(v))
+		case uint16:
+			return writeShortestHeader(enc.writer, mtUint, uint64(v))
+		case uint32:
+			return writeShortestHeader(enc.writer, mtUint, uint64(v))
+		case uint64:
+			return writeShortestHeader(enc.writer, mtUint, v)
+		case uint:
+			return writeShortestHeader(enc.writer, mtUint, uint64(v))
+		case int8:
+			return writeShortestInt(enc.writer, int64(v))
+		case int16:
+			return writeShortestInt(enc.writer, int64(v))
+		case int32:
+			return writeShortestInt(enc.writer, int64(v))
+		case int64:
+			return writeShortestInt(enc.writer, v)
+		case int:
+			return writeShortestInt(enc.writer, int64(v))
+		}
+	}
+
 	// 3. Specification of the CBOR Encoding.
 
 	switch v := item.(type) {
@@ -247,16 +468,21 @@ func (enc *Encoder) encodePrimitiveTypes(item any) error {
 			return err
 		}
 		return writeNint64Bytes(enc.writer, int64(v))
+	case Float16:
+		if v != v {
+			return encodeNaN(float16NaNToFloat64Bits(v.Bits()), fpnFloat16)
+		}
+		return encodeFloat(float64(v), fpnFloat16)
 	case float32:
-		if err := writeHeader(enc.writer, mtFloat, fpnFloat32); err != nil {
-			return err
+		if v != v {
+			return encodeNaN(float32NaNToFloat64Bits(math.Float32bits(v)), fpnFloat32)
 		}
-		return writeFloat32Bytes(enc.writer, v)
+		return encodeFloat(float64(v), fpnFloat32)
 	case float64:
-		if err := writeHeader(enc.writer, mtFloat, fpnFloat64); err != nil {
-			return err
+		if math.IsNaN(v) {
+			return encodeNaN(math.Float64bits(v), fpnFloat64)
 		}
-		return writeFloat64Bytes(enc.writer, v)
+		return encodeFloat(v, fpnFloat64)
 	case bool:
 		return encodeBool(v)
 	case nil:
@@ -270,6 +496,46 @@ func (enc *Encoder) encodePrimitiveTypes(item any) error {
 	return newErrorNotSupportedNativeType(item)
 }
 
+// shortestFloatInfo returns the shortest floating-point format which represents the specified value without losing precision.
+func shortestFloatInfo(v float64) majorInfo {
+	bits := math.Float64bits(v)
+	v32 := float32(v)
+	if math.Float64bits(float64(v32)) != bits {
+		return fpnFloat64
+	}
+	if math.Float64bits(float64(float16BitsToFloat32(float32ToFloat16Bits(v32)))) != bits {
+		return fpnFloat32
+	}
+	return fpnFloat16
+}
+
+// shortestNaNInfo returns the shortest floating-point format which represents the specified NaN bits without losing the payload.
+func shortestNaNInfo(bits uint64) majorInfo {
+	mant := bits & float64MantMask
+	switch {
+	case mant&(1<<float64To16MantBits-1) == 0:
+		return fpnFloat16
+	case mant&(1<<float64To32MantBits-1) == 0:
+		return fpnFloat32
+	}
+	return fpnFloat64
+}
+
+// writeCanonicalNaN writes the canonical quiet NaN in the specified floating-point format.
+func writeCanonicalNaN(w io.Writer, fpn majorInfo) error {
+	if err := writeHeader(w, mtFloat, fpn); err != nil {
+		return err
+	}
+	switch fpn {
+	case fpnFloat16:
+		return writeUint16Bytes(w, canonicalNaN16)
+	case fpnFloat32:
+		return writeUint32Bytes(w, canonicalNaN32)
+	default:
+		return writeUint64Bytes(w, canonicalNaN64)
+	}
+}
+
 func (enc *Encoder) encodeArray(item any) error {
 	writeAnyArray := func(v []any) error {
 		cnt := len(v)
@@ -318,14 +584,63 @@ func encodeMapWithSort[K comparable, V any](enc *Encoder, m map[K]V) error {
 	return nil
 }
 
+// encodeMapWithKeySort encodes the specified map pairs in the order of the encoded keys sorted by the specified comparison function.
+func (enc *Encoder) encodeMapWithKeySort(m map[any]any, cmp func(a, b []byte) int) error {
+	type encodedPair struct {
+		key []byte
+		val any
+	}
+	pairs := make([]encodedPair, 0, len(m))
+	for k, v := range m {
+		var buf bytes.Buffer
+		keyEnc := &Encoder{
+			Config:      enc.Config,
+			writer:      &buf,
+			indefinites: []majorType{},
+		}
+		if err := keyEnc.Encode(k); err != nil {
+			return err
+		}
+		pairs = append(pairs, encodedPair{key: buf.Bytes(), val: v})
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return cmp(pairs[i].key, pairs[j].key) < 0
+	})
+	for n, pair := range pairs {
+		if 0 < n && bytes.Equal(pairs[n-1].key, pair.key) {
+			return newErrorDuplicateMapKey(pair.key)
+		}
+		if err := writeBytes(enc.writer, pair.key); err != nil {
+			return err
+		}
+		if err := enc.Encode(pair.val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compareLengthFirst compares the specified encoded keys by their lengths first and then bytewise.
+func compareLengthFirst(a, b []byte) int {
+	if len(a) != len(b) {
+		return len(a) - len(b)
+	}
+	return bytes.Compare(a, b)
+}
+
 func (enc *Encoder) encodeMap(item any) error {
 	writeAnyMap := func(m map[any]any) error {
 		if err := enc.encodeNumberOfBytes(mtMap, len(m)); err != nil {
 			return err
 		}
 
-		if enc.MapSortEnabled {
+		switch enc.mapSortMode() {
+		case MapSortString:
 			return encodeMapWithSort(enc, m)
+		case MapSortBytewise:
+			return enc.encodeMapWithKeySort(m, bytes.Compare)
+		case MapSortLengthFirst:
+			return enc.encodeMapWithKeySort(m, compareLengthFirst)
 		}
 
 		for k, v := range m {
@@ -357,35 +672,119 @@ func (enc *Encoder) encodeMap(item any) error {
 func (enc *Encoder) encodeStdStruct(item any) error {
 	switch v := item.(type) {
 	case time.Time:
-		if err := writeHeader(enc.writer, mtTag, tagStdDateTime); err != nil {
-			return err
+		switch enc.TimeMode {
+		case TimeUnix:
+			if err := writeTagHeader(enc.writer, tagEpochDateTime); err != nil {
+				return err
+			}
+			return writeShortestInt(enc.writer, v.Unix())
+		case TimeUnixFloat:
+			if err := writeTagHeader(enc.writer, tagEpochDateTime); err != nil {
+				return err
+			}
+			return enc.Encode(float64(v.Unix()) + float64(v.Nanosecond())/float64(time.Second))
+		case TimeExtended:
+			return enc.encodeExtendedTime(v)
+		case TimeRFC3339Nano:
+			if err := writeTagHeader(enc.writer, tagStdDateTime); err != nil {
+				return err
+			}
+			return enc.encodeTextString(v.Format(time.RFC3339Nano))
+		default:
+			if err := writeTagHeader(enc.writer, tagStdDateTime); err != nil {
+				return err
+			}
+			return enc.encodeTextString(v.Format(time.RFC3339))
 		}
-		return enc.encodeTextString(v.Format(time.RFC3339))
 	default:
 		return newErrorNotSupportedNativeType(item)
 	}
 }
 
-// nolint: exhaustive
-func (enc *Encoder) encodeStruct(item any) error {
-	var itemStruct reflect.Value
-	switch reflect.TypeOf(item).Kind() {
-	case reflect.Struct:
-		itemStruct = reflect.ValueOf(item)
-	case reflect.Pointer:
-		itemStruct = reflect.ValueOf(item).Elem()
-		if itemStruct.Type().Kind() != reflect.Struct {
-			return newErrorNotSupportedNativeType(item)
+// encodeExtendedTime encodes the specified time as the extended time of RFC 9581 whose keys are written in the bytewise order.
+func (enc *Encoder) encodeExtendedTime(v time.Time) error {
+	nsec := v.Nanosecond()
+	tzHint, hasTzHint := timezoneHintOf(v)
+	n := uint64(1)
+	if nsec != 0 {
+		n++
+	}
+	if hasTzHint {
+		n++
+	}
+	if err := writeTagHeader(enc.writer, tagExtendedTime); err != nil {
+		return err
+	}
+	if err := writeShortestHeader(enc.writer, mtMap, n); err != nil {
+		return err
+	}
+	if err := writeShortestInt(enc.writer, timeKeyBaseTime); err != nil {
+		return err
+	}
+	if err := writeShortestInt(enc.writer, v.Unix()); err != nil {
+		return err
+	}
+	if nsec != 0 {
+		if err := writeShortestInt(enc.writer, timeKeyNanoseconds); err != nil {
+			return err
 		}
-	default:
+		if err := writeShortestInt(enc.writer, int64(nsec)); err != nil {
+			return err
+		}
+	}
+	if hasTzHint {
+		if err := writeShortestInt(enc.writer, timeKeyTimezoneHint); err != nil {
+			return err
+		}
+		return enc.encodeTextString(tzHint)
+	}
+	return nil
+}
+
+func (enc *Encoder) encodeStruct(item any) error {
+	itemStruct := reflect.ValueOf(item)
+	if itemStruct.Kind() != reflect.Struct {
 		return newErrorNotSupportedNativeType(item)
 	}
+	// Copy the struct to an addressable value to encode fields whose pointers marshal themselves.
+	if !itemStruct.CanAddr() {
+		addrStruct := reflect.New(itemStruct.Type()).Elem()
+		addrStruct.Set(itemStruct)
+		itemStruct = addrStruct
+	}
+
+	// Structs with the toarray option are encoded as arrays of all fields in the declaration order.
+	toArray := isStructToArray(itemStruct.Type())
+
+	fields := structFieldsOf(itemStruct.Type())
+	fieldNames := make([]any, 0, len(fields))
+	fieldVals := make([]any, 0, len(fields))
+	for _, field := range fields {
+		fieldVal, ok := structFieldValueOf(itemStruct, field)
+		if !ok {
+			if toArray {
+				fieldNames = append(fieldNames, field.key)
+				fieldVals = append(fieldVals, nil)
+			}
+			continue
+		}
+		if !toArray && field.omitEmpty && isEmptyValue(fieldVal) {
+			continue
+		}
+		if fieldVal.CanAddr() && !isMarshalerType(fieldVal.Type()) && isMarshalerType(fieldVal.Addr().Type()) {
+			fieldVal = fieldVal.Addr()
+		}
+		fieldNames = append(fieldNames, field.key)
+		fieldVals = append(fieldVals, fieldVal.Interface())
+	}
+
+	if toArray {
+		return enc.encodeArray(fieldVals)
+	}
 
-	structMap := map[any]any{}
-	numField := itemStruct.NumField()
-	for n := range numField {
-		typeField := itemStruct.Type().Field(n)
-		structMap[typeField.Name] = itemStruct.Field(n).Interface()
+	structMap := make(map[any]any, len(fieldNames))
+	for n, fieldName := range fieldNames {
+		structMap[fieldName] = fieldVals[n]
 	}
 	return enc.encodeMap(structMap)
 }
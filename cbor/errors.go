@@ -25,15 +25,39 @@ var ErrUnmarshal = errors.New("unmarshal error")
 var ErrDecode = errors.New("decode error")
 var ErrEncode = errors.New("encode error")
 
+// errBreak is an internal error to notify the break stop code of indefinite-length items.
+var errBreak = errors.New("break")
+
 const (
-	errorUnkonwnNativeType      = "%T (%v) is %w"
-	errorUnkonwnMajorType       = "major type (%d) is %w"
-	errorUnkonwnAdditionalInfo  = "major type (%d:%d) is %w"
-	errorUnmarshalDataTypes     = "%w : cound not convert from %v (%T) to %T"
-	errorUnmarshalShortArray    = "%w : short array size (%T(%d) < %T(%d))"
-	errorUnmarshalCastTypes     = "%w : cound not cast from %v (%T) to %T"
-	errorSortedMapEncode        = "%w : map key (%v:%T) could not be sorted"
-	errorUnmarshalReflectValues = "%w : cound not convert from %v to %T"
+	errorUnkonwnNativeType       = "%T (%v) is %w"
+	errorUnkonwnMajorType        = "major type (%d) is %w"
+	errorUnkonwnAdditionalInfo   = "major type (%d:%d) is %w"
+	errorUnmarshalDataTypes      = "%w : cound not convert from %v (%T) to %T"
+	errorUnmarshalShortArray     = "%w : short array size (%T(%d) < %T(%d))"
+	errorUnmarshalCastTypes      = "%w : cound not cast from %v (%T) to %T"
+	errorSortedMapEncode         = "%w : map key (%v:%T) could not be sorted"
+	errorUnmarshalReflectValues  = "%w : cound not convert from %v to %T"
+	errorIncomparableMapKey      = "%w : map key (%v:%T) is not comparable"
+	errorIndefiniteChunk         = "%w : chunk major type (%d:%d) is invalid in indefinite-length major type (%d)"
+	errorIndefiniteChunkType     = "%w : %v (%T) is invalid as a chunk of indefinite-length major type (%d)"
+	errorIndefiniteNotBegun      = "%w : no indefinite-length item is begun"
+	errorDuplicateMapKey         = "%w : map key (%x) is duplicated"
+	errorDeterministicIndefinite = "%w : indefinite-length items are not allowed in the deterministic encoding"
+	errorEpochDateTime           = "%w : %v (%T) is invalid as epoch-based date/time"
+	errorExtendedTime            = "%w : %v (%T) is invalid as extended time"
+	errorBignum                  = "%w : %v (%T) is invalid as bignum (tag %d)"
+	errorFraction                = "%w : %v (%T) is invalid as decimal fraction or bigfloat (tag %d)"
+	errorRational                = "%w : %v (%T) is invalid as rational number"
+	errorTagReserved             = "%w : tag (%d) is reserved"
+	errorTagRegistered           = "%w : tag (%d) is already registered"
+	errorTagType                 = "%w : %T is invalid or already registered as tag (%d)"
+	errorSimpleValue             = "%w : simple value (%d) is not well-formed"
+	errorUndefined               = "%w : undefined could not be stored to %T"
+	errorComplex                 = "%w : %v (%T) is invalid as complex number"
+	errorNumberOfItemsOverflow   = "%w : number of items (%d) of major type (%d) overflows int"
+	errorEmbeddedPointer         = "%w : nil embedded pointer to unexported struct (%v) could not be allocated"
+	errorStructArraySize         = "%w : array size (%d) does not match the number of fields (%d) of %v"
+	errorMarshalerItem           = "%w : %T returned %x which is not a single well-formed data item"
 )
 
 func newErrorNotSupportedMajorType(m majorType) error {
@@ -67,3 +91,87 @@ func newErrorSortedMapEncode(key any) error {
 func newErrorUnmarshalReflectValues(from reflect.Value, to reflect.Value) error {
 	return fmt.Errorf(errorUnmarshalReflectValues, ErrUnmarshal, from.Kind().String(), to.Kind().String())
 }
+
+func newErrorIncomparableMapKey(key any) error {
+	return fmt.Errorf(errorIncomparableMapKey, ErrDecode, key, key)
+}
+
+func newErrorIndefiniteChunk(m majorType, chunkType majorType, chunkInfo majorInfo) error {
+	return fmt.Errorf(errorIndefiniteChunk, ErrDecode, (chunkType >> 5), chunkInfo, (m >> 5))
+}
+
+func newErrorIndefiniteChunkType(m majorType, item any) error {
+	return fmt.Errorf(errorIndefiniteChunkType, ErrEncode, item, item, (m >> 5))
+}
+
+func newErrorIndefiniteNotBegun() error {
+	return fmt.Errorf(errorIndefiniteNotBegun, ErrEncode)
+}
+
+func newErrorDuplicateMapKey(key []byte) error {
+	return fmt.Errorf(errorDuplicateMapKey, ErrEncode, key)
+}
+
+func newErrorDeterministicIndefinite() error {
+	return fmt.Errorf(errorDeterministicIndefinite, ErrEncode)
+}
+
+func newErrorEpochDateTime(v any) error {
+	return fmt.Errorf(errorEpochDateTime, ErrDecode, v, v)
+}
+
+func newErrorExtendedTime(v any) error {
+	return fmt.Errorf(errorExtendedTime, ErrDecode, v, v)
+}
+
+func newErrorBignum(tag tagNumber, v any) error {
+	return fmt.Errorf(errorBignum, ErrDecode, v, v, tag)
+}
+
+func newErrorNumberOfItemsOverflow(m majorType, n uint64) error {
+	return fmt.Errorf(errorNumberOfItemsOverflow, ErrDecode, n, (m >> 5))
+}
+
+func newErrorFraction(tag tagNumber, v any) error {
+	return fmt.Errorf(errorFraction, ErrDecode, v, v, tag)
+}
+
+func newErrorRational(v any) error {
+	return fmt.Errorf(errorRational, ErrDecode, v, v)
+}
+
+func newErrorTagReserved(number uint64) error {
+	return fmt.Errorf(errorTagReserved, ErrNotSupported, number)
+}
+
+func newErrorTagRegistered(number uint64) error {
+	return fmt.Errorf(errorTagRegistered, ErrNotSupported, number)
+}
+
+func newErrorTagType(number uint64, v any) error {
+	return fmt.Errorf(errorTagType, ErrNotSupported, v, number)
+}
+
+func newErrorSimpleValue(v uint8) error {
+	return fmt.Errorf(errorSimpleValue, ErrDecode, v)
+}
+
+func newErrorUndefined(to any) error {
+	return fmt.Errorf(errorUndefined, ErrUnmarshal, to)
+}
+
+func newErrorComplex(v any) error {
+	return fmt.Errorf(errorComplex, ErrDecode, v, v)
+}
+
+func newErrorEmbeddedPointer(t reflect.Type) error {
+	return fmt.Errorf(errorEmbeddedPointer, ErrUnmarshal, t)
+}
+
+func newErrorStructArraySize(size int, fields int, t reflect.Type) error {
+	return fmt.Errorf(errorStructArraySize, ErrUnmarshal, size, fields, t)
+}
+
+func newErrorMarshalerItem(v any, b []byte) error {
+	return fmt.Errorf(errorMarshalerItem, ErrEncode, v, b)
+}
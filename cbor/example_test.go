@@ -51,7 +51,7 @@ func ExampleDecoder_Decode() {
 	// 1000
 	// -1000
 	// 1.1
-	// <nil>
+	// 5.960464477539063e-08
 	// false
 	// true
 	// <nil>
@@ -89,7 +89,7 @@ func ExampleUnmarshal() {
 	// 1000
 	// -1000
 	// 1.1
-	// <nil>
+	// 5.960464477539063e-08
 	// false
 	// true
 	// <nil>
@@ -233,10 +233,9 @@ func ExampleMarshal() {
 		[]int{1, 2, 3},
 		map[any]any{"a": "A"},
 		struct {
-			Key   string
-			Value string
+			Key string
 		}{
-			Key: "hello", Value: "world",
+			Key: "hello",
 		},
 	}
 	for _, goObj := range goObjs {
@@ -257,5 +256,5 @@ func ExampleMarshal() {
 	// c074323031332d30332d32315432303a30343a30305a
 	// 831b00000000000000011b00000000000000021b0000000000000003
 	// a161616141
-	// a2634b65796568656c6c6f6556616c756565776f726c64
+	// a1634b65796568656c6c6f
 }
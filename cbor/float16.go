@@ -0,0 +1,39 @@
+// Copyright (C) 2022 The go-cbor Authors All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cbor
+
+// Float16 represents a half-precision floating-point number which is encoded as an IEEE 754 binary16 value.
+// The value is rounded to the nearest binary16 value when encoding.
+type Float16 float32
+
+// NewFloat16FromBits returns a new half-precision floating-point number from the specified IEEE 754 binary16 bits.
+func NewFloat16FromBits(bits uint16) Float16 {
+	return Float16(float16BitsToFloat32(bits))
+}
+
+// Bits returns the IEEE 754 binary16 bits of the half-precision floating-point number.
+func (f Float16) Bits() uint16 {
+	return float32ToFloat16Bits(float32(f))
+}
+
+// Float32 returns the half-precision floating-point number as a float32 value.
+func (f Float16) Float32() float32 {
+	return float32(f)
+}
+
+// Float64 returns the half-precision floating-point number as a float64 value.
+func (f Float16) Float64() float64 {
+	return float64(f)
+}
@@ -0,0 +1,139 @@
+// Copyright (C) 2022 The go-cbor Authors All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cbor
+
+import (
+	"math/big"
+
+	"github.com/cybergarage/go-safecast/safecast"
+)
+
+// DecimalFraction represents a decimal fraction (tag 4) whose value is Mantissa * 10^Exponent.
+type DecimalFraction struct {
+	Exponent int64
+	Mantissa *big.Int
+}
+
+// BigFloat represents a bigfloat (tag 5) whose value is Mantissa * 2^Exponent.
+type BigFloat struct {
+	Exponent int64
+	Mantissa *big.Int
+}
+
+// NewBigFloat returns a new bigfloat which represents the specified floating-point number exactly.
+func NewBigFloat(f *big.Float) (BigFloat, error) {
+	if f.IsInf() {
+		return BigFloat{}, newErrorNotSupportedNativeType(f)
+	}
+	mant := new(big.Float)
+	exp := f.MantExp(mant)
+	prec := int(f.MinPrec())
+	mant.SetMantExp(mant, prec)
+	m, _ := mant.Int(nil)
+	return BigFloat{Exponent: int64(exp - prec), Mantissa: m}, nil
+}
+
+// BigFloat returns the decimal fraction as a floating-point number.
+// The value is rounded to the precision of the mantissa plus 64 bits.
+func (d DecimalFraction) BigFloat() *big.Float {
+	m := mantissaOf(d.Mantissa)
+	prec := uint(max(m.BitLen(), 64) + 64) // nolint: gosec
+	f := new(big.Float).SetPrec(prec).SetInt(m)
+	if m.Sign() == 0 {
+		return f
+	}
+	e := pow10Float(absExponentOf(d.Exponent), prec)
+	if d.Exponent < 0 {
+		return f.Quo(f, e)
+	}
+	return f.Mul(f, e)
+}
+
+// BigFloat returns the bigfloat as a floating-point number.
+func (b BigFloat) BigFloat() *big.Float {
+	m := mantissaOf(b.Mantissa)
+	prec := max(m.BitLen(), 64)
+	f := new(big.Float).SetPrec(uint(prec)).SetInt(m) // nolint: gosec
+	// Clamp the untrusted exponent so that it still overflows to infinity or underflows to zero
+	// without wrapping around when added to the mantissa's exponent.
+	limit := int64(prec) + 1
+	exp := min(max(b.Exponent, big.MinExp-limit), big.MaxExp+limit)
+	return f.SetMantExp(f, int(exp))
+}
+
+// mantissaOf returns the specified mantissa, or zero if the mantissa is nil.
+func mantissaOf(m *big.Int) *big.Int {
+	if m == nil {
+		return new(big.Int)
+	}
+	return m
+}
+
+// absExponentOf returns the absolute value of the specified exponent without overflow.
+func absExponentOf(v int64) uint64 {
+	if v < 0 {
+		return uint64(-(v + 1)) + 1
+	}
+	return uint64(v)
+}
+
+// pow10Float returns 10^n with the specified precision by repeated squaring.
+// The computation is bounded by the precision regardless of the untrusted exponent, and overflows to infinity.
+func pow10Float(n uint64, prec uint) *big.Float {
+	p := new(big.Float).SetPrec(prec).SetInt64(1)
+	base := new(big.Float).SetPrec(prec).SetInt64(10)
+	for ; 0 < n; n >>= 1 {
+		if n&1 == 1 {
+			p.Mul(p, base)
+		}
+		if p.IsInf() {
+			break
+		}
+		base.Mul(base, base)
+	}
+	return p
+}
+
+// encodeFraction encodes the specified exponent and mantissa as a decimal fraction or bigfloat.
+func (enc *Encoder) encodeFraction(tag tagNumber, exp int64, mant *big.Int) error {
+	// 3.4.4. Decimal Fractions and Bigfloats.
+	if err := writeTagHeader(enc.writer, tag); err != nil {
+		return err
+	}
+	if err := writeShortestHeader(enc.writer, mtArray, 2); err != nil {
+		return err
+	}
+	if err := writeShortestInt(enc.writer, exp); err != nil {
+		return err
+	}
+	return enc.encodeInteger(mantissaOf(mant))
+}
+
+// fractionOf returns the exponent and mantissa of the specified decimal fraction or bigfloat content.
+func fractionOf(tag tagNumber, v any) (int64, *big.Int, error) {
+	items, ok := v.([]any)
+	if !ok || len(items) != 2 {
+		return 0, nil, newErrorFraction(tag, v)
+	}
+	var exp int64
+	if err := safecast.ToInt64(items[0], &exp); err != nil {
+		return 0, nil, newErrorFraction(tag, v)
+	}
+	mant, ok := toBigInt(items[1])
+	if !ok {
+		return 0, nil, newErrorFraction(tag, v)
+	}
+	return exp, mant, nil
+}
@@ -15,6 +15,8 @@
 package cbor
 
 import (
+	"bytes"
+	"errors"
 	"io"
 	"math"
 	"reflect"
@@ -38,12 +40,16 @@ func writeString(w io.Writer, val string) error {
 	return writeBytes(w, []byte(val))
 }
 
+// readBytes reads the specified number of bytes without allocating them in advance, because the number is untrusted.
 func readBytes(r io.Reader, n int) ([]byte, error) {
-	buf := make([]byte, n)
-	if _, err := io.ReadFull(r, buf); err != nil {
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, r, int64(n)); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		}
 		return nil, err
 	}
-	return buf, nil
+	return buf.Bytes(), nil
 }
 
 ////////////////////////////////////////////////////////////
@@ -56,6 +62,47 @@ func writeHeader(w io.Writer, m majorType, i majorInfo) error {
 	return writeByte(w, header)
 }
 
+// writeShortestHeader writes a header with the specified argument in the shortest form.
+func writeShortestHeader(w io.Writer, m majorType, v uint64) error {
+	switch {
+	case v < uint64(aiOneByte):
+		return writeHeader(w, m, majorInfo(v))
+	case v <= math.MaxUint8:
+		if err := writeHeader(w, m, aiOneByte); err != nil {
+			return err
+		}
+		return writeUint8Bytes(w, uint8(v))
+	case v <= math.MaxUint16:
+		if err := writeHeader(w, m, aiTwoByte); err != nil {
+			return err
+		}
+		return writeUint16Bytes(w, uint16(v))
+	case v <= math.MaxUint32:
+		if err := writeHeader(w, m, aiFourByte); err != nil {
+			return err
+		}
+		return writeUint32Bytes(w, uint32(v))
+	default:
+		if err := writeHeader(w, m, aiEightByte); err != nil {
+			return err
+		}
+		return writeUint64Bytes(w, v)
+	}
+}
+
+// writeShortestInt writes the specified integer as an unsigned or negative integer in the shortest form.
+func writeShortestInt(w io.Writer, v int64) error {
+	if 0 <= v {
+		return writeShortestHeader(w, mtUint, uint64(v))
+	}
+	return writeShortestHeader(w, mtNInt, uint64(-(v + 1)))
+}
+
+// writeTagHeader writes a tag header with the specified tag number in the shortest form.
+func writeTagHeader(w io.Writer, tag tagNumber) error {
+	return writeShortestHeader(w, mtTag, uint64(tag))
+}
+
 ////////////////////////////////////////////////////////////
 // int8
 ////////////////////////////////////////////////////////////
@@ -94,14 +141,6 @@ func writeUint8Bytes(w io.Writer, v uint8) error {
 // nint8 (CBOR)
 ////////////////////////////////////////////////////////////
 
-func readNint8Bytes(r io.Reader) (int8, error) {
-	v, err := readUint8Bytes(r)
-	if err != nil {
-		return 0, err
-	}
-	return -int8(v + 1), nil
-}
-
 func writeNint8Bytes(w io.Writer, v int8) error {
 	return writeUint8Bytes(w, uint8(-(v + 1)))
 }
@@ -148,14 +187,6 @@ func writeUint16Bytes(w io.Writer, v uint16) error {
 // nint16 (CBOR)
 ////////////////////////////////////////////////////////////
 
-func readNint16Bytes(r io.Reader) (int16, error) {
-	v, err := readUint16Bytes(r)
-	if err != nil {
-		return 0, err
-	}
-	return -int16(v + 1), nil
-}
-
 func writeNint16Bytes(w io.Writer, v int16) error {
 	return writeUint16Bytes(w, uint16(-(v + 1)))
 }
@@ -206,14 +237,6 @@ func writeUint32Bytes(w io.Writer, v uint32) error {
 // nint32 (CBOR)
 ////////////////////////////////////////////////////////////
 
-func readNint32Bytes(r io.Reader) (int32, error) {
-	v, err := readUint32Bytes(r)
-	if err != nil {
-		return 0, err
-	}
-	return -int32(v + 1), nil
-}
-
 func writeNint32Bytes(w io.Writer, v int32) error {
 	return writeUint32Bytes(w, uint32(-(v + 1)))
 }
@@ -272,16 +295,136 @@ func writeUint64Bytes(w io.Writer, v uint64) error {
 // nint64 (CBOR)
 ////////////////////////////////////////////////////////////
 
-func readNint64Bytes(r io.Reader) (int64, error) {
-	v, err := readUint64Bytes(r)
+func writeNint64Bytes(w io.Writer, v int64) error {
+	return writeUint64Bytes(w, uint64(-(v + 1)))
+}
+
+////////////////////////////////////////////////////////////
+// float16
+////////////////////////////////////////////////////////////
+
+const (
+	float16SignMask     = 0x8000
+	float16ExpMask      = 0x7C00
+	float16MantMask     = 0x03FF
+	float16MantBits     = 10
+	float16ExpBias      = 15
+	float32ExpBias      = 127
+	float32MantBits     = 23
+	float32ExpMax       = 0xFF
+	float32MantMask     = 0x7FFFFF
+	float32ImplicitBit  = 0x800000
+	float32To16MantBits = float32MantBits - float16MantBits
+	float32SignMask     = 0x80000000
+	float64SignMask     = 0x8000000000000000
+	float64ExpMask      = 0x7FF0000000000000
+	float64MantMask     = 0x000FFFFFFFFFFFFF
+	float64MantBits     = 52
+	float64To16MantBits = float64MantBits - float16MantBits
+	float64To32MantBits = float64MantBits - float32MantBits
+)
+
+// NaN payloads are converted between the formats by their bits,
+// because the float conversions of Go may quiet signaling NaNs.
+
+// float16NaNToFloat64Bits returns the float64 bits of the specified binary16 NaN bits keeping the payload.
+func float16NaNToFloat64Bits(h uint16) uint64 {
+	return uint64(h&float16SignMask)<<48 | float64ExpMask | uint64(h&float16MantMask)<<float64To16MantBits
+}
+
+// float32NaNToFloat64Bits returns the float64 bits of the specified binary32 NaN bits keeping the payload.
+func float32NaNToFloat64Bits(b uint32) uint64 {
+	return uint64(b&float32SignMask)<<32 | float64ExpMask | uint64(b&float32MantMask)<<float64To32MantBits
+}
+
+// float64NaNToFloat16Bits returns the binary16 bits of the specified float64 NaN bits truncating the lower payload bits.
+func float64NaNToFloat16Bits(b uint64) uint16 {
+	return uint16(b>>48)&float16SignMask | float16ExpMask | uint16(b>>float64To16MantBits)&float16MantMask
+}
+
+// float64NaNToFloat32Bits returns the binary32 bits of the specified float64 NaN bits truncating the lower payload bits.
+func float64NaNToFloat32Bits(b uint64) uint32 {
+	return uint32(b>>32)&float32SignMask | float32ExpMax<<float32MantBits | uint32(b>>float64To32MantBits)&float32MantMask
+}
+
+// float16BitsToFloat32 converts the specified IEEE 754 binary16 bits into the equivalent float32 value.
+func float16BitsToFloat32(h uint16) float32 {
+	sign := uint32(h&float16SignMask) << 16
+	exp := uint32(h&float16ExpMask) >> float16MantBits
+	mant := uint32(h & float16MantMask)
+	switch exp {
+	case 0:
+		// Zero and subnormal numbers (mant * 2^-24)
+		v := float32(mant) / (1 << 24)
+		if sign != 0 {
+			v = -v
+		}
+		return v
+	case float16ExpMask >> float16MantBits:
+		// Infinity and NaN with the payload
+		return math.Float32frombits(sign | float32ExpMax<<float32MantBits | mant<<float32To16MantBits)
+	}
+	exp = exp - float16ExpBias + float32ExpBias
+	return math.Float32frombits(sign | exp<<float32MantBits | mant<<float32To16MantBits)
+}
+
+// float32ToFloat16Bits converts the specified float32 value into the nearest IEEE 754 binary16 bits rounding half to even.
+func float32ToFloat16Bits(f float32) uint16 {
+	b := math.Float32bits(f)
+	sign := uint16(b>>16) & float16SignMask
+	exp := int32(b>>float32MantBits) & float32ExpMax
+	mant := b & float32MantMask
+
+	roundToNearestEven := func(v uint32, rem uint32, halfway uint32) uint32 {
+		if halfway < rem || (rem == halfway && v&1 == 1) {
+			return v + 1
+		}
+		return v
+	}
+
+	if exp == float32ExpMax {
+		if mant == 0 {
+			return sign | float16ExpMask
+		}
+		// Keep the upper payload bits and the quiet bit of NaN
+		nan := uint16(mant >> float32To16MantBits)
+		if nan == 0 {
+			nan = 1 << (float16MantBits - 1)
+		}
+		return sign | float16ExpMask | nan
+	}
+
+	exp = exp - float32ExpBias + float16ExpBias
+	switch {
+	case (float16ExpMask >> float16MantBits) <= exp:
+		// Overflow
+		return sign | float16ExpMask
+	case exp <= 0:
+		// Subnormal numbers or underflow
+		if exp < -float16MantBits {
+			return sign
+		}
+		mant |= float32ImplicitBit
+		shift := uint32(float32To16MantBits + 1 - exp)
+		v := roundToNearestEven(mant>>shift, mant&((1<<shift)-1), 1<<(shift-1))
+		return sign | uint16(v)
+	}
+
+	v := uint32(exp)<<float16MantBits | mant>>float32To16MantBits
+	v = roundToNearestEven(v, mant&((1<<float32To16MantBits)-1), 1<<(float32To16MantBits-1))
+	return sign | uint16(v)
+}
+
+func readFloat16Bytes(r io.Reader) (float32, error) {
+	v, err := readUint16Bytes(r)
 	if err != nil {
 		return 0, err
 	}
-	return -int64(v + 1), nil
+	return float16BitsToFloat32(v), nil
 }
 
-func writeNint64Bytes(w io.Writer, v int64) error {
-	return writeUint64Bytes(w, uint64(-(v + 1)))
+func writeFloat16Bytes(w io.Writer, v float32) error {
+	return writeUint16Bytes(w, float32ToFloat16Bits(v))
 }
 
 ////////////////////////////////////////////////////////////
@@ -242,6 +242,54 @@ func TestEncodeDecodeFunc(t *testing.T) {
 			})
 		}
 	})
+	t.Run("float16", func(t *testing.T) {
+		for bits := range math.MaxUint16 + 1 {
+			testVal := uint16(bits)
+			var w bytes.Buffer
+			err := writeFloat16Bytes(&w, float16BitsToFloat32(testVal))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			reader := bytes.NewReader(w.Bytes())
+			val, err := readUint16Bytes(reader)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if val != testVal {
+				t.Errorf("%04x != %04x", val, testVal)
+			}
+		}
+	})
+	t.Run("float16-rounding", func(t *testing.T) {
+		tests := []struct {
+			value    float32
+			expected uint16
+		}{
+			{value: 1.0, expected: 0x3C00},
+			{value: 1.0 + 1.0/2048, expected: 0x3C00},   // halfway to even
+			{value: 1.0 + 3.0/2048, expected: 0x3C02},   // halfway to even
+			{value: 1.0 + 1.5/2048, expected: 0x3C01},   // above halfway
+			{value: 65504.0, expected: 0x7BFF},          // max normal
+			{value: 65520.0, expected: 0x7C00},          // overflow to infinity
+			{value: 0.00006103515625, expected: 0x0400}, // min normal
+			{value: 5.960464477539063e-8, expected: 0x0001},
+			{value: 2.9802322387695312e-8, expected: 0x0000}, // halfway to zero
+			{value: 2.9802326e-8, expected: 0x0001},
+			{value: -4.0, expected: 0xC400},
+			{value: float32(math.Inf(1)), expected: 0x7C00},
+			{value: float32(math.Inf(-1)), expected: 0xFC00},
+		}
+		for _, test := range tests {
+			if bits := float32ToFloat16Bits(test.value); bits != test.expected {
+				t.Errorf("%v: %04x != %04x", test.value, bits, test.expected)
+			}
+		}
+		if bits := float32ToFloat16Bits(float32(math.NaN())); bits&0x7C00 != 0x7C00 || bits&0x03FF == 0 {
+			t.Errorf("NaN: %04x", bits)
+		}
+	})
 	t.Run("float32", func(t *testing.T) {
 		testValues := []float32{
 			-math.MaxFloat32,
@@ -16,8 +16,32 @@ package cbor
 
 import (
 	"bytes"
+	"encoding"
+	"reflect"
 )
 
+// Marshaler is the interface implemented by types that can marshal themselves into a valid CBOR data item.
+type Marshaler interface {
+	MarshalCBOR() ([]byte, error)
+}
+
+var (
+	marshalerType       = reflect.TypeFor[Marshaler]()
+	binaryMarshalerType = reflect.TypeFor[encoding.BinaryMarshaler]()
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// isMarshalerType returns true if the specified type marshals itself.
+func isMarshalerType(t reflect.Type) bool {
+	return t.Implements(marshalerType) || t.Implements(binaryMarshalerType) || t.Implements(textMarshalerType)
+}
+
+// isNilPointer returns true if the specified value is a nil pointer.
+func isNilPointer(v any) bool {
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Pointer && rv.IsNil()
+}
+
 // Marshal returns the CBOR-encoded bytes of the specified v.
 func Marshal(v any) ([]byte, error) {
 	var writer bytes.Buffer
@@ -0,0 +1,101 @@
+// Copyright (C) 2022 The go-cbor Authors All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cbor
+
+import (
+	"math"
+	"math/big"
+	"reflect"
+)
+
+var (
+	bigRatType    = reflect.TypeFor[big.Rat]()
+	bigRatPtrType = reflect.TypeFor[*big.Rat]()
+)
+
+// encodeRat encodes the specified rational number as a numerator and denominator array with the rational number tag.
+func (enc *Encoder) encodeRat(v *big.Rat) error {
+	if v == nil {
+		return enc.encodePrimitiveTypes(nil)
+	}
+	if err := writeTagHeader(enc.writer, tagRational); err != nil {
+		return err
+	}
+	if err := writeShortestHeader(enc.writer, mtArray, 2); err != nil {
+		return err
+	}
+	if err := enc.encodeInteger(v.Num()); err != nil {
+		return err
+	}
+	return enc.encodeInteger(v.Denom())
+}
+
+// ratOf returns the rational number of the specified numerator and denominator array.
+func ratOf(v any) (*big.Rat, error) {
+	items, ok := v.([]any)
+	if !ok || len(items) != 2 {
+		return nil, newErrorRational(v)
+	}
+	num, ok := toBigInt(items[0])
+	if !ok {
+		return nil, newErrorRational(v)
+	}
+	denom, ok := toBigInt(items[1])
+	if !ok || denom.Sign() <= 0 {
+		return nil, newErrorRational(v)
+	}
+	return new(big.Rat).SetFrac(num, denom), nil
+}
+
+// nolint: exhaustive
+// unmarshalRatTo stores the specified item to the specified destination if the item is a rational number.
+// A rational number is stored to a floating-point destination as the nearest value.
+func unmarshalRatTo(fromObj any, toVal reflect.Value) (bool, error) {
+	r, ok := fromObj.(*big.Rat)
+	if !ok || !toVal.IsValid() {
+		return false, nil
+	}
+
+	for toVal.Kind() == reflect.Pointer && toVal.Type() != bigRatPtrType {
+		if toVal.IsNil() {
+			return false, nil
+		}
+		toVal = toVal.Elem()
+	}
+
+	if !toVal.CanSet() {
+		return false, nil
+	}
+
+	switch toVal.Type() {
+	case bigRatPtrType:
+		toVal.Set(reflect.ValueOf(new(big.Rat).Set(r)))
+		return true, nil
+	case bigRatType:
+		toVal.Set(reflect.ValueOf(*new(big.Rat).Set(r)))
+		return true, nil
+	}
+
+	switch toVal.Kind() {
+	case reflect.Float32, reflect.Float64:
+		f, _ := r.Float64()
+		if math.IsInf(f, 0) || toVal.OverflowFloat(f) {
+			return true, newErrorUnmarshalDataTypes(fromObj, toVal.Interface())
+		}
+		toVal.SetFloat(f)
+		return true, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,34 @@
+// Copyright (C) 2022 The go-cbor Authors All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cbor
+
+// RawMessage represents the encoded bytes of a single data item.
+// RawMessage captures the exact encoded bytes of the item when unmarshaling to defer decoding,
+// and the encoded bytes are written verbatim when encoding.
+type RawMessage []byte
+
+// MarshalCBOR returns the encoded bytes of the raw message, or null if the raw message is nil or empty.
+func (m RawMessage) MarshalCBOR() ([]byte, error) {
+	if len(m) == 0 {
+		return []byte{byte(mtFloat) | byte(simpNull)}, nil
+	}
+	return m, nil
+}
+
+// UnmarshalCBOR stores a copy of the specified encoded bytes to the raw message.
+func (m *RawMessage) UnmarshalCBOR(data []byte) error {
+	*m = append((*m)[0:0], data...)
+	return nil
+}
@@ -0,0 +1,67 @@
+// Copyright (C) 2022 The go-cbor Authors All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cbor
+
+import (
+	"reflect"
+)
+
+// SimpleValue represents a simple value of major type 7 which has no content.
+// The simple values 20 to 23 are false, true, null, and undefined, and 24 to 31 are reserved and not well-formed.
+type SimpleValue uint8
+
+// Undefined represents the undefined simple value.
+type Undefined struct{}
+
+var undefinedType = reflect.TypeFor[Undefined]()
+
+// encodeSimpleValue encodes the specified simple value.
+func (enc *Encoder) encodeSimpleValue(v SimpleValue) error {
+	// 3.3. Floating-Point Numbers and Values with No Content.
+	switch {
+	case v < SimpleValue(aiOneByte):
+		return writeHeader(enc.writer, mtFloat, majorInfo(v))
+	case v < simpMinOneByte:
+		return newErrorNotSupportedNativeType(v)
+	}
+	if err := writeHeader(enc.writer, mtFloat, aiOneByte); err != nil {
+		return err
+	}
+	return writeUint8Bytes(enc.writer, uint8(v))
+}
+
+// unmarshalUndefinedTo stores the specified undefined value to the specified destination as the undefined mode.
+func (dec *Decoder) unmarshalUndefinedTo(fromObj any, toVal reflect.Value) (bool, error) {
+	if _, ok := fromObj.(Undefined); !ok || !toVal.IsValid() {
+		return false, nil
+	}
+	for !toVal.CanSet() && toVal.Kind() == reflect.Pointer && !toVal.IsNil() {
+		toVal = toVal.Elem()
+	}
+	if !toVal.CanSet() {
+		return false, nil
+	}
+	if toVal.Type() == undefinedType {
+		toVal.Set(reflect.ValueOf(fromObj))
+		return true, nil
+	}
+	switch dec.UndefinedMode {
+	case UndefinedError:
+		return true, newErrorUndefined(toVal.Interface())
+	default:
+		toVal.SetZero()
+		return true, nil
+	}
+}
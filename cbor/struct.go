@@ -0,0 +1,234 @@
+// Copyright (C) 2022 The go-cbor Authors All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cbor
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	structTagKey       = "cbor"
+	structTagSkip      = "-"
+	structTagOmitEmpty = "omitempty"
+	structTagToArray   = "toarray"
+	structTagKeyAsInt  = "keyasint"
+	structOptionsField = "_"
+)
+
+// structField represents a struct field with the options specified by the cbor struct tag.
+type structField struct {
+	name      string
+	key       any
+	index     []int
+	typ       reflect.Type
+	tagged    bool
+	omitEmpty bool
+}
+
+// structIntKey represents an integer map key of a struct field with the keyasint option.
+type structIntKey int64
+
+// MarshalCBOR encodes the integer key in the shortest form.
+func (k structIntKey) MarshalCBOR() ([]byte, error) {
+	var w bytes.Buffer
+	if err := writeShortestInt(&w, int64(k)); err != nil {
+		return nil, err
+	}
+	return w.Bytes(), nil
+}
+
+var structFieldsCache sync.Map
+
+// structFieldsOf returns the encodable fields of the specified struct type.
+// As encoding/json, fields of anonymous embedded structs are promoted to the specified struct type.
+func structFieldsOf(t reflect.Type) []structField {
+	if fields, ok := structFieldsCache.Load(t); ok {
+		return fields.([]structField) // nolint: forcetypeassert
+	}
+
+	fields := dominantStructFields(embeddedStructFieldsOf(t, nil, map[reflect.Type]bool{}))
+
+	structFieldsCache.Store(t, fields)
+
+	return fields
+}
+
+// embeddedStructFieldsOf returns all fields of the specified struct type including the promoted fields in the declaration order.
+func embeddedStructFieldsOf(t reflect.Type, index []int, visited map[reflect.Type]bool) []structField {
+	if visited[t] {
+		return nil
+	}
+	visited[t] = true
+	defer delete(visited, t)
+
+	fields := []structField{}
+	for n := range t.NumField() {
+		typeField := t.Field(n)
+		tag, hasTag := typeField.Tag.Lookup(structTagKey)
+		if tag == structTagSkip {
+			continue
+		}
+		fieldIndex := append(append([]int{}, index...), n)
+		opts := strings.Split(tag, ",")
+		if typeField.Anonymous {
+			embeddedType := indirectTypeOf(typeField.Type)
+			if embeddedType.Kind() == reflect.Struct && len(opts[0]) == 0 {
+				fields = append(fields, embeddedStructFieldsOf(embeddedType, fieldIndex, visited)...)
+				continue
+			}
+		}
+		if !typeField.IsExported() {
+			continue
+		}
+		field := structField{
+			name:      typeField.Name,
+			key:       typeField.Name,
+			index:     fieldIndex,
+			typ:       typeField.Type,
+			tagged:    false,
+			omitEmpty: false,
+		}
+		if hasTag {
+			if 0 < len(opts[0]) {
+				field.name = opts[0]
+				field.key = opts[0]
+				field.tagged = true
+			}
+			for _, opt := range opts[1:] {
+				switch opt {
+				case structTagOmitEmpty:
+					field.omitEmpty = true
+				case structTagKeyAsInt:
+					// Names which are not integers are used as text string keys.
+					if v, err := strconv.ParseInt(field.name, 10, 64); err == nil {
+						field.key = structIntKey(v)
+					}
+				}
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// dominantStructFields returns the fields which are not hidden by the other fields with the same name as encoding/json.
+// The shallowest field is dominant, and the tagged field is dominant in the same depth. Otherwise, all of them are dropped.
+func dominantStructFields(fields []structField) []structField {
+	dominants := []structField{}
+	for _, field := range fields {
+		dominant := true
+		for _, other := range fields {
+			if other.name != field.name || slices.Equal(other.index, field.index) {
+				continue
+			}
+			switch {
+			case len(other.index) < len(field.index):
+				dominant = false
+			case len(other.index) == len(field.index) && (other.tagged || !field.tagged):
+				dominant = false
+			}
+		}
+		if dominant {
+			dominants = append(dominants, field)
+		}
+	}
+	return dominants
+}
+
+// isStructToArray returns true if the specified struct type has a "_" field with the toarray option to be encoded as an array.
+func isStructToArray(t reflect.Type) bool {
+	for n := range t.NumField() {
+		typeField := t.Field(n)
+		if typeField.Name != structOptionsField {
+			continue
+		}
+		opts := strings.Split(typeField.Tag.Get(structTagKey), ",")
+		if slices.Contains(opts[1:], structTagToArray) {
+			return true
+		}
+	}
+	return false
+}
+
+// structFieldByKey returns the struct field which has the specified text string or integer map key.
+func structFieldByKey(t reflect.Type, key any) (structField, bool) {
+	switch v := reflect.ValueOf(key); {
+	case v.Kind() == reflect.String:
+	case v.CanInt():
+		key = structIntKey(v.Int())
+	case v.CanUint() && v.Uint() <= math.MaxInt64:
+		key = structIntKey(v.Uint()) // nolint: gosec
+	default:
+		return structField{}, false
+	}
+	for _, field := range structFieldsOf(t) {
+		if field.key == key {
+			return field, true
+		}
+	}
+	return structField{}, false
+}
+
+// structFieldValueOf returns the field value of the specified struct value, or returns false if the field is in a nil embedded pointer.
+func structFieldValueOf(v reflect.Value, field structField) (reflect.Value, bool) {
+	fieldVal, err := v.FieldByIndexErr(field.index)
+	if err != nil {
+		return reflect.Value{}, false
+	}
+	return fieldVal, true
+}
+
+// settableStructFieldValueOf returns the field value of the specified struct value with allocating nil embedded pointers.
+func settableStructFieldValueOf(v reflect.Value, field structField) (reflect.Value, error) {
+	for n, index := range field.index {
+		if 0 < n && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, newErrorEmbeddedPointer(v.Type().Elem())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(index)
+	}
+	return v, nil
+}
+
+// nolint: exhaustive
+// isEmptyValue returns true if the specified value is regarded as empty for omitempty.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,200 @@
+// Copyright (C) 2022 The go-cbor Authors All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cbor
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Tag represents a tagged data item whose tag number is not handled by this package or a tag set.
+type Tag struct {
+	Number  uint64
+	Content any
+}
+
+// RawTag represents a tagged data item whose content is kept as the encoded bytes of a single data item.
+// A tagged data item is decoded as RawTag when the destination of Unmarshal() is RawTag, and the content is written verbatim when encoding.
+type RawTag struct {
+	Number  uint64
+	Content []byte
+}
+
+var rawTagType = reflect.TypeFor[RawTag]()
+
+// TagEncodeFunc returns the content of the tag for the specified value of the registered type.
+type TagEncodeFunc func(v any) (any, error)
+
+// TagDecodeFunc returns a value of the registered type from the specified decoded content of the tag.
+type TagDecodeFunc func(content any) (any, error)
+
+// tagEntry represents a user-defined tag which is registered in a tag set.
+type tagEntry struct {
+	number tagNumber
+	typ    reflect.Type
+	encode TagEncodeFunc
+	decode TagDecodeFunc
+}
+
+// TagSet represents a set of user-defined tags which are bound to Go types.
+type TagSet struct {
+	mutex   sync.RWMutex
+	numbers map[tagNumber]*tagEntry
+	types   map[reflect.Type]*tagEntry
+}
+
+// NewTagSet returns a new empty tag set.
+func NewTagSet() *TagSet {
+	return &TagSet{
+		mutex:   sync.RWMutex{},
+		numbers: map[tagNumber]*tagEntry{},
+		types:   map[reflect.Type]*tagEntry{},
+	}
+}
+
+// Add registers the specified tag number with the type of the specified value.
+// The encode function returns the content of the tag, and the value itself is encoded as the content if the function is nil.
+// The decode function builds the value from the decoded content, and the content is unmarshaled into a new value of the type if the function is nil.
+func (ts *TagSet) Add(number uint64, v any, encode TagEncodeFunc, decode TagDecodeFunc) error {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return newErrorTagType(number, v)
+	}
+	tag := tagNumber(number)
+	if isBuiltinTag(tag) {
+		return newErrorTagReserved(number)
+	}
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+	if _, ok := ts.numbers[tag]; ok {
+		return newErrorTagRegistered(number)
+	}
+	if _, ok := ts.types[t]; ok {
+		return newErrorTagType(number, v)
+	}
+	entry := &tagEntry{
+		number: tag,
+		typ:    t,
+		encode: encode,
+		decode: decode,
+	}
+	ts.numbers[tag] = entry
+	ts.types[t] = entry
+	return nil
+}
+
+// Remove unregisters the specified tag number.
+func (ts *TagSet) Remove(number uint64) {
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+	entry, ok := ts.numbers[tagNumber(number)]
+	if !ok {
+		return
+	}
+	delete(ts.numbers, entry.number)
+	delete(ts.types, entry.typ)
+}
+
+// entryByNumber returns the registered tag of the specified tag number.
+func (ts *TagSet) entryByNumber(number tagNumber) (*tagEntry, bool) {
+	if ts == nil {
+		return nil, false
+	}
+	ts.mutex.RLock()
+	defer ts.mutex.RUnlock()
+	entry, ok := ts.numbers[number]
+	return entry, ok
+}
+
+// entryByType returns the registered tag of the specified type.
+func (ts *TagSet) entryByType(t reflect.Type) (*tagEntry, bool) {
+	if ts == nil || t == nil {
+		return nil, false
+	}
+	ts.mutex.RLock()
+	defer ts.mutex.RUnlock()
+	entry, ok := ts.types[t]
+	return entry, ok
+}
+
+// isRegisteredTagValue returns true if the type of the specified value is registered in the specified tag set.
+func isRegisteredTagValue(ts *TagSet, v any) bool {
+	_, ok := ts.entryByType(reflect.TypeOf(v))
+	return ok
+}
+
+// isBuiltinTag returns true if the specified tag number is handled by this package.
+func isBuiltinTag(tag tagNumber) bool {
+	switch tag {
+	case tagStdDateTime, tagEpochDateTime, tagPositiveBignum, tagNegativeBignum, tagDecimalFraction, tagBigFloat, tagRational, tagExtendedTime, tagComplex:
+		return true
+	}
+	return false
+}
+
+// encodeRegisteredTag encodes the specified item with the registered tag if the type of the item is registered.
+func (enc *Encoder) encodeRegisteredTag(item any) (bool, error) {
+	if enc.TagSet == nil || item == nil {
+		return false, nil
+	}
+	v := reflect.ValueOf(item)
+	entry, ok := enc.TagSet.entryByType(v.Type())
+	if !ok && v.Kind() == reflect.Pointer && !v.IsNil() {
+		entry, ok = enc.TagSet.entryByType(v.Type().Elem())
+		item = v.Elem().Interface()
+	}
+	if !ok {
+		return false, nil
+	}
+	if err := writeTagHeader(enc.writer, entry.number); err != nil {
+		return true, err
+	}
+	if entry.encode == nil {
+		return true, enc.encodeUntagged(item)
+	}
+	content, err := entry.encode(item)
+	if err != nil {
+		return true, err
+	}
+	return true, enc.Encode(content)
+}
+
+// decodeRegisteredTag returns a value of the registered type from the specified decoded content.
+func (dec *Decoder) decodeRegisteredTag(entry *tagEntry, content any) (any, error) {
+	if entry.decode != nil {
+		return entry.decode(content)
+	}
+	v := reflect.New(entry.typ)
+	if err := dec.unmarshalTo(content, v.Interface()); err != nil {
+		return nil, err
+	}
+	return v.Elem().Interface(), nil
+}
+
+// encodeTag encodes the specified tag number and content.
+func (enc *Encoder) encodeTag(number uint64, content any) error {
+	if err := writeTagHeader(enc.writer, tagNumber(number)); err != nil {
+		return err
+	}
+	return enc.Encode(content)
+}
+
+// encodeRawTag encodes the specified tag number and writes the specified encoded content verbatim.
+func (enc *Encoder) encodeRawTag(number uint64, content []byte) error {
+	if err := writeTagHeader(enc.writer, tagNumber(number)); err != nil {
+		return err
+	}
+	return writeBytes(enc.writer, content)
+}
@@ -0,0 +1,142 @@
+// Copyright (C) 2022 The go-cbor Authors All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cbor
+
+import (
+	"math"
+	"time"
+
+	"github.com/cybergarage/go-safecast/safecast"
+)
+
+const (
+	// RFC 9581: 3. Extended Time Format.
+	timeKeyBaseTime     int64 = 1
+	timeKeyMilliseconds int64 = -3
+	timeKeyMicroseconds int64 = -6
+	timeKeyNanoseconds  int64 = -9
+	timeKeyTimezoneHint int64 = -10
+)
+
+const (
+	timezoneOffsetLayout = "-07:00"
+)
+
+// epochTimeOf returns the time of the specified epoch-based date/time seconds.
+func epochTimeOf(v any) (time.Time, error) {
+	switch sec := v.(type) {
+	case int8, int16, int32, int64, uint8, uint16, uint32, uint64:
+		var unixSec int64
+		if err := safecast.ToInt64(sec, &unixSec); err != nil {
+			return time.Time{}, newErrorEpochDateTime(v)
+		}
+		return time.Unix(unixSec, 0).UTC(), nil
+	case float32:
+		return floatEpochTimeOf(float64(sec))
+	case float64:
+		return floatEpochTimeOf(sec)
+	}
+	return time.Time{}, newErrorEpochDateTime(v)
+}
+
+// floatEpochTimeOf returns the time of the specified floating-point epoch-based date/time seconds.
+func floatEpochTimeOf(v float64) (time.Time, error) {
+	if math.IsNaN(v) || math.IsInf(v, 0) || v < math.MinInt64 || math.MaxInt64 <= v {
+		return time.Time{}, newErrorEpochDateTime(v)
+	}
+	sec, frac := math.Modf(v)
+	return time.Unix(int64(sec), int64(math.Round(frac*1e9))).UTC(), nil
+}
+
+// extendedTimeOf returns the time of the specified extended time map of RFC 9581.
+func extendedTimeOf(v any) (time.Time, error) {
+	m, ok := v.(map[any]any)
+	if !ok {
+		return time.Time{}, newErrorExtendedTime(v)
+	}
+	var t time.Time
+	var hasBaseTime bool
+	var fracNsec int64
+	var loc *time.Location
+	for key, val := range m {
+		var k int64
+		if err := safecast.ToInt64(key, &k); err != nil {
+			continue
+		}
+		switch k {
+		case timeKeyBaseTime:
+			baseTime, err := epochTimeOf(val)
+			if err != nil {
+				return time.Time{}, err
+			}
+			t = baseTime
+			hasBaseTime = true
+		case timeKeyMilliseconds, timeKeyMicroseconds, timeKeyNanoseconds:
+			var frac int64
+			if err := safecast.ToInt64(val, &frac); err != nil {
+				return time.Time{}, newErrorExtendedTime(v)
+			}
+			for ; timeKeyNanoseconds < k; k -= 3 {
+				frac *= 1000
+			}
+			fracNsec = frac
+		case timeKeyTimezoneHint:
+			if hint, ok := val.(string); ok {
+				loc = locationOfTimezoneHint(hint)
+			}
+		default:
+			// Positive keys are critical and the unknown ones must not be ignored.
+			if 0 < k {
+				return time.Time{}, newErrorExtendedTime(v)
+			}
+		}
+	}
+	if !hasBaseTime {
+		return time.Time{}, newErrorExtendedTime(v)
+	}
+	t = t.Add(time.Duration(fracNsec))
+	if loc != nil {
+		t = t.In(loc)
+	}
+	return t, nil
+}
+
+// timezoneHintOf returns the time zone hint of the specified time, an IANA time zone name or a numeric offset such as "+09:00".
+func timezoneHintOf(t time.Time) (string, bool) {
+	loc := t.Location()
+	switch loc {
+	case time.UTC:
+		return "", false
+	case time.Local:
+	default:
+		if _, err := time.LoadLocation(loc.String()); err == nil && loc.String() != "" {
+			return loc.String(), true
+		}
+	}
+	return t.Format(timezoneOffsetLayout), true
+}
+
+// locationOfTimezoneHint returns the location of the specified time zone hint, or nil if the hint is unknown.
+func locationOfTimezoneHint(hint string) *time.Location {
+	if t, err := time.Parse(timezoneOffsetLayout, hint); err == nil {
+		_, offset := t.Zone()
+		return time.FixedZone(hint, offset)
+	}
+	loc, err := time.LoadLocation(hint)
+	if err != nil {
+		return nil
+	}
+	return loc
+}
@@ -16,12 +16,37 @@ package cbor
 
 import (
 	"bytes"
+	"encoding"
+	"math/big"
 	"reflect"
 	"time"
 
 	"github.com/cybergarage/go-safecast/safecast"
 )
 
+// Unmarshaler is the interface implemented by types that can unmarshal a CBOR description of themselves.
+// UnmarshalCBOR receives the encoded bytes of a single CBOR data item.
+type Unmarshaler interface {
+	UnmarshalCBOR(data []byte) error
+}
+
+var (
+	unmarshalerType       = reflect.TypeFor[Unmarshaler]()
+	binaryUnmarshalerType = reflect.TypeFor[encoding.BinaryUnmarshaler]()
+	textUnmarshalerType   = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// rawItem represents the encoded bytes of a data item which is passed to the destination without being decoded.
+type rawItem []byte
+
+// isRawItemType returns true if the specified destination type requires the encoded bytes.
+func isRawItemType(t reflect.Type) bool {
+	if t == nil {
+		return false
+	}
+	return t.Implements(unmarshalerType) || reflect.PointerTo(t).Implements(unmarshalerType)
+}
+
 // Unmarshal decodes the specified CBOR-encoded bytes and returns the data representation of Go. Unmarshal is a sugar function of Decoder::Decode().
 func Unmarshal(cborBytes []byte) (any, error) {
 	decoder := NewDecoder(bytes.NewReader(cborBytes))
@@ -37,10 +62,26 @@ func UnmarshalTo(cborBytes []byte, s any) error {
 // nolint: exhaustive
 // Unmarshal decodes a next encoded item from the specified reader and stores the decoded item to the specified data type if appropriate.
 func (dec *Decoder) Unmarshal(toObj any) error {
-	fromObj, err := dec.Decode()
+	fromObj, err := dec.decode(reflect.TypeOf(toObj))
 	if err != nil {
 		return err
 	}
+	return dec.unmarshalTo(fromObj, toObj)
+}
+
+// nolint: exhaustive
+// unmarshalTo stores the specified decoded item to the specified data type if appropriate.
+func (dec *Decoder) unmarshalTo(fromObj any, toObj any) error {
+	if ok, err := dec.unmarshalUserTypeTo(fromObj, reflect.ValueOf(toObj)); ok {
+		return err
+	}
+
+	if toVal := reflect.ValueOf(toObj); toVal.Kind() == reflect.Pointer && !toVal.IsNil() {
+		// Null and pointer destinations such as **T are unmarshaled as values to allocate or release the pointers.
+		if fromObj == nil || toVal.Elem().Kind() == reflect.Pointer || isRegisteredTagValue(dec.TagSet, fromObj) {
+			return dec.unmarshalValueToValue(reflect.ValueOf(fromObj), toVal.Elem())
+		}
+	}
 
 	switch from := fromObj.(type) {
 	case map[any]any:
@@ -51,20 +92,32 @@ func (dec *Decoder) Unmarshal(toObj any) error {
 			return dec.unmarshalMapToMap(from, toObj)
 		case reflect.Pointer:
 			elem := reflect.ValueOf(toObj).Elem()
-			if elem.Type().Kind() != reflect.Struct {
+			switch elem.Type().Kind() {
+			case reflect.Struct:
+				return dec.unmarshalMapToStruct(from, elem)
+			case reflect.Map:
+				if elem.IsNil() {
+					elem.Set(reflect.MakeMap(elem.Type()))
+				}
+				return dec.unmarshalMapToMap(from, elem.Interface())
+			default:
 				return newErrorUnmarshalDataTypes(fromObj, toObj)
 			}
-			return dec.unmarshalMapToStruct(from, elem)
 		default:
 			return newErrorUnmarshalDataTypes(fromObj, toObj)
 		}
 	case []any:
 		switch reflect.ValueOf(toObj).Type().Kind() {
-		case reflect.Array, reflect.Slice, reflect.Pointer:
+		case reflect.Pointer:
+			if elem := reflect.ValueOf(toObj).Elem(); elem.Kind() == reflect.Struct {
+				return dec.unmarshalArrayToStruct(reflect.ValueOf(fromObj), elem)
+			}
+			return dec.unmarshalArrayToArray(reflect.ValueOf(fromObj), reflect.ValueOf(toObj))
+		case reflect.Array, reflect.Slice:
 			return dec.unmarshalArrayToArray(reflect.ValueOf(fromObj), reflect.ValueOf(toObj))
 		}
 		return newErrorUnmarshalDataTypes(fromObj, toObj)
-	case time.Time:
+	case time.Time, DecimalFraction, BigFloat, Tag, RawTag, SimpleValue:
 		return dec.unmarshalEmbedTypeTo(fromObj, toObj)
 	}
 
@@ -76,7 +129,6 @@ func (dec *Decoder) unmarshalArrayToArray(fromArrayVal reflect.Value, toArrayVal
 	// NOTE: The Laws of Reflection - The Go Programming Language
 	// https://go.dev/blog/laws-of-reflection
 
-	fromArrayType := fromArrayVal.Type()
 	fromArrayLen := fromArrayVal.Len()
 	toArrayType := toArrayVal.Type()
 	switch toArrayType.Kind() {
@@ -89,7 +141,7 @@ func (dec *Decoder) unmarshalArrayToArray(fromArrayVal reflect.Value, toArrayVal
 			if !toArrayVal.CanSet() {
 				return newErrorUnmarshalArraySize(fromArrayVal, toArrayVal)
 			}
-			toArrayVal.Set(reflect.MakeSlice(fromArrayType, fromArrayLen, fromArrayLen))
+			toArrayVal.Set(reflect.MakeSlice(toArrayType, fromArrayLen, fromArrayLen))
 		}
 	case reflect.Pointer:
 		elem := toArrayVal.Elem()
@@ -98,6 +150,7 @@ func (dec *Decoder) unmarshalArrayToArray(fromArrayVal reflect.Value, toArrayVal
 			if elem.Len() < fromArrayLen {
 				return newErrorUnmarshalArraySize(fromArrayVal, toArrayVal)
 			}
+			toArrayVal = elem
 		case reflect.Slice:
 			if elem.Len() < fromArrayLen {
 				if !elem.CanSet() {
@@ -126,6 +179,27 @@ func (dec *Decoder) unmarshalArrayToArray(fromArrayVal reflect.Value, toArrayVal
 	return nil
 }
 
+func (dec *Decoder) unmarshalArrayToStruct(fromArrayVal reflect.Value, toStructVal reflect.Value) error {
+	toStructType := toStructVal.Type()
+	if toStructType.Kind() != reflect.Struct || !isStructToArray(toStructType) {
+		return newErrorUnmarshalDataTypes(fromArrayVal.Interface(), toStructVal.Interface())
+	}
+	fields := structFieldsOf(toStructType)
+	if fromArrayVal.Len() != len(fields) {
+		return newErrorStructArraySize(fromArrayVal.Len(), len(fields), toStructType)
+	}
+	for n, field := range fields {
+		toStructField, err := settableStructFieldValueOf(toStructVal, field)
+		if err != nil {
+			return err
+		}
+		if err := dec.unmarshalValueToValue(fromArrayVal.Index(n), toStructField); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (dec *Decoder) unmarshalMapToMap(fromMap map[any]any, toMap any) error {
 	toMapVal := reflect.ValueOf(toMap)
 	toMapType := toMapVal.Type()
@@ -136,14 +210,21 @@ func (dec *Decoder) unmarshalMapToMap(fromMap map[any]any, toMap any) error {
 	toMapElemType := toMapType.Elem()
 	for fromMapKey, fromMapValue := range fromMap {
 		fromMapKeyVal := reflect.ValueOf(fromMapKey)
+		toMapKeyVal := reflect.New(toMapKeyType).Elem()
+		if ok, err := dec.unmarshalUserTypeTo(fromMapKey, toMapKeyVal); ok {
+			if err != nil {
+				return err
+			}
+			fromMapKeyVal = toMapKeyVal
+		}
 		if !fromMapKeyVal.CanConvert(toMapKeyType) {
 			return newErrorUnmarshalDataTypes(fromMapKey, toMapVal)
 		}
-		fromMapElemVal := reflect.ValueOf(fromMapValue)
-		if !fromMapElemVal.CanConvert(toMapElemType) {
-			return newErrorUnmarshalDataTypes(fromMapKey, toMapVal)
+		toMapElemVal := reflect.New(toMapElemType).Elem()
+		if err := dec.unmarshalValueToValue(reflect.ValueOf(fromMapValue), toMapElemVal); err != nil {
+			return err
 		}
-		toMapVal.SetMapIndex(fromMapKeyVal.Convert(toMapKeyType), fromMapElemVal.Convert(toMapElemType))
+		toMapVal.SetMapIndex(fromMapKeyVal.Convert(toMapKeyType), toMapElemVal)
 	}
 	return nil
 }
@@ -153,46 +234,41 @@ func (dec *Decoder) unmarshalMapToStruct(fromMap map[any]any, toStructVal reflec
 		return newErrorUnmarshalDataTypes(fromMap, toStructVal)
 	}
 	for fromMapKey, fromMapElem := range fromMap {
-		key, ok := fromMapKey.(string)
+		field, ok := structFieldByKey(toStructVal.Type(), fromMapKey)
 		if !ok {
 			return newErrorUnmarshalDataTypes(fromMap, toStructVal)
 		}
-		toStructField := toStructVal.FieldByName(key)
-		if !toStructField.IsValid() {
-			return newErrorUnmarshalDataTypes(fromMap, toStructVal)
-		}
-		fromMapElemVal := reflect.ValueOf(fromMapElem)
-		fromMapElemKind := fromMapElemVal.Type().Kind()
-		toStructFieldKind := toStructField.Type().Kind()
-		if fromMapElemKind == toStructFieldKind {
-			toStructField.Set(fromMapElemVal)
-			continue
+		toStructField, err := settableStructFieldValueOf(toStructVal, field)
+		if err != nil {
+			return err
 		}
-		switch toStructFieldKind { //nolint:exhaustive
-		case reflect.Struct:
-			fromMapElemMap, ok := fromMapElem.(map[any]any)
-			if !ok {
-				return newErrorUnmarshalDataTypes(fromMap, toStructVal)
-			}
-			if err := dec.unmarshalMapToStruct(fromMapElemMap, toStructField); err != nil {
-				return err
-			}
-		default:
-			if err := dec.unmarshalValueToValue(fromMapElemVal, toStructField); err != nil {
-				return err
-			}
+		if err := dec.unmarshalValueToValue(reflect.ValueOf(fromMapElem), toStructField); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
 func (dec *Decoder) unmarshalValueToValue(fromVal reflect.Value, toVal reflect.Value) error {
+	if fromVal.Kind() == reflect.Interface {
+		fromVal = fromVal.Elem()
+	}
+	// Null sets nil to pointers, interfaces, maps, and slices, and leaves other values unchanged.
+	if !fromVal.IsValid() {
+		switch toVal.Kind() { // nolint: exhaustive
+		case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+			toVal.SetZero()
+		}
+		return nil
+	}
 	from := fromVal.Interface()
+	if ok, err := dec.unmarshalUserTypeTo(from, toVal); ok {
+		return err
+	}
 	fromType := fromVal.Type()
-	fromKind := fromType.Kind()
 	toType := toVal.Type()
 	toKind := toType.Kind()
-	if fromKind == toKind {
+	if fromType.AssignableTo(toType) {
 		toVal.Set(fromVal)
 		return nil
 	}
@@ -287,6 +363,27 @@ func (dec *Decoder) unmarshalValueToValue(fromVal reflect.Value, toVal reflect.V
 		}
 	case reflect.Array, reflect.Slice:
 		return dec.unmarshalArrayToArray(fromVal, toVal)
+	case reflect.Pointer:
+		if toVal.IsNil() {
+			toVal.Set(reflect.New(toType.Elem()))
+		}
+		return dec.unmarshalValueToValue(fromVal, toVal.Elem())
+	case reflect.Struct:
+		switch v := from.(type) {
+		case map[any]any:
+			return dec.unmarshalMapToStruct(v, toVal)
+		case []any:
+			return dec.unmarshalArrayToStruct(fromVal, toVal)
+		}
+	case reflect.Map:
+		fromMap, ok := from.(map[any]any)
+		if !ok {
+			break
+		}
+		if toVal.IsNil() {
+			toVal.Set(reflect.MakeMap(toType))
+		}
+		return dec.unmarshalMapToMap(fromMap, toVal.Interface())
 	}
 	return newErrorUnmarshalReflectValues(fromVal, toVal)
 }
@@ -329,6 +426,16 @@ func (dec *Decoder) unmarshalBasicTypeTo(fromObj any, toObj any) error {
 		return nil
 	case string:
 		return safecast.FromString(from, toObj)
+	case complex128:
+		switch to := toObj.(type) {
+		case *complex128:
+			*to = from
+		case *complex64:
+			*to = complex64(from)
+		default:
+			return newErrorUnmarshalDataTypes(fromObj, toObj)
+		}
+		return nil
 	default:
 	}
 	return newErrorUnmarshalDataTypes(fromObj, toObj)
@@ -343,8 +450,108 @@ func (dec *Decoder) unmarshalEmbedTypeTo(fromObj any, toObj any) error {
 		case *time.Time:
 			*to = from
 		}
+	case DecimalFraction:
+		switch to := toObj.(type) {
+		case *DecimalFraction:
+			*to = from
+		case *big.Float:
+			to.Set(from.BigFloat())
+		default:
+			return newErrorUnmarshalDataTypes(fromObj, toObj)
+		}
+	case SimpleValue:
+		to, ok := toObj.(*SimpleValue)
+		if !ok {
+			return newErrorUnmarshalDataTypes(fromObj, toObj)
+		}
+		*to = from
+	case Tag:
+		to, ok := toObj.(*Tag)
+		if !ok {
+			return newErrorUnmarshalDataTypes(fromObj, toObj)
+		}
+		*to = from
+	case RawTag:
+		to, ok := toObj.(*RawTag)
+		if !ok {
+			return newErrorUnmarshalDataTypes(fromObj, toObj)
+		}
+		*to = from
+	case BigFloat:
+		switch to := toObj.(type) {
+		case *BigFloat:
+			*to = from
+		case *big.Float:
+			to.Set(from.BigFloat())
+		default:
+			return newErrorUnmarshalDataTypes(fromObj, toObj)
+		}
 	default:
 		return newErrorUnmarshalDataTypes(fromObj, toObj)
 	}
 	return nil
 }
+
+// unmarshalUserTypeTo unmarshals the specified item with the unmarshaler of the specified destination if the destination is a user-defined type which unmarshals itself.
+func (dec *Decoder) unmarshalUserTypeTo(fromObj any, toVal reflect.Value) (bool, error) {
+	if ok, err := unmarshalBigIntTo(fromObj, toVal); ok {
+		return true, err
+	}
+	if ok, err := unmarshalRatTo(fromObj, toVal); ok {
+		return true, err
+	}
+	if ok, err := dec.unmarshalUndefinedTo(fromObj, toVal); ok {
+		return true, err
+	}
+	switch from := fromObj.(type) {
+	case rawItem:
+		// Null sets nil to pointers without calling their unmarshalers.
+		if toVal.Kind() == reflect.Pointer && toVal.CanSet() && len(from) == 1 && from[0] == byte(mtFloat)|byte(simpNull) {
+			toVal.SetZero()
+			return true, nil
+		}
+		unmarshaler, ok := unmarshalerOf(toVal, unmarshalerType)
+		if !ok {
+			return true, newErrorUnmarshalDataTypes(fromObj, toVal.Interface())
+		}
+		return true, unmarshaler.(Unmarshaler).UnmarshalCBOR(from) // nolint: forcetypeassert
+	case ByteString:
+		return dec.unmarshalUserTypeTo(from.Bytes(), toVal)
+	case []byte:
+		if unmarshaler, ok := unmarshalerOf(toVal, binaryUnmarshalerType); ok {
+			return true, unmarshaler.(encoding.BinaryUnmarshaler).UnmarshalBinary(from) // nolint: forcetypeassert
+		}
+		if unmarshaler, ok := unmarshalerOf(toVal, textUnmarshalerType); ok {
+			return true, unmarshaler.(encoding.TextUnmarshaler).UnmarshalText(from) // nolint: forcetypeassert
+		}
+	case string:
+		if unmarshaler, ok := unmarshalerOf(toVal, textUnmarshalerType); ok {
+			return true, unmarshaler.(encoding.TextUnmarshaler).UnmarshalText([]byte(from)) // nolint: forcetypeassert
+		}
+		if unmarshaler, ok := unmarshalerOf(toVal, binaryUnmarshalerType); ok {
+			return true, unmarshaler.(encoding.BinaryUnmarshaler).UnmarshalBinary([]byte(from)) // nolint: forcetypeassert
+		}
+	}
+	return false, nil
+}
+
+// unmarshalerOf returns the specified destination as the specified unmarshaler interface if the destination implements it.
+// A nil pointer destination is allocated if possible.
+func unmarshalerOf(toVal reflect.Value, unmarshalerType reflect.Type) (any, bool) {
+	if !toVal.IsValid() {
+		return nil, false
+	}
+	if toVal.Kind() != reflect.Pointer || !toVal.Type().Implements(unmarshalerType) {
+		if !toVal.CanAddr() || !toVal.Addr().Type().Implements(unmarshalerType) {
+			return nil, false
+		}
+		toVal = toVal.Addr()
+	}
+	if toVal.IsNil() {
+		if !toVal.CanSet() {
+			return nil, false
+		}
+		toVal.Set(reflect.New(toVal.Type().Elem()))
+	}
+	return toVal.Interface(), true
+}
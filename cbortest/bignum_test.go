@@ -0,0 +1,144 @@
+// Copyright (C) 2022 The go-cbor Authors All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cbortest
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/cybergarage/go-cbor/cbor"
+)
+
+type BignumStruct struct {
+	Amount    big.Int
+	AmountPtr *big.Int
+	Small     *big.Int
+}
+
+type BignumIntStruct struct {
+	Amount    uint64
+	AmountPtr int64
+	Small     int8
+}
+
+func TestBignum(t *testing.T) {
+	t.Run("encode", func(t *testing.T) {
+		tests := []struct {
+			value    any
+			expected string
+		}{
+			{value: big.NewInt(0), expected: "c240"},
+			{value: big.NewInt(-1), expected: "c340"},
+			{value: *big.NewInt(256), expected: "c2420100"},
+			{value: (*big.Int)(nil), expected: "f6"},
+		}
+		for _, test := range tests {
+			t.Run(test.expected, func(t *testing.T) {
+				encoded, err := cbor.Marshal(test.value)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if hex.EncodeToString(encoded) != test.expected {
+					t.Errorf("%s != %s", hex.EncodeToString(encoded), test.expected)
+				}
+			})
+		}
+	})
+
+	t.Run("shortest", func(t *testing.T) {
+		tests := []struct {
+			value    *big.Int
+			expected string
+		}{
+			{value: big.NewInt(-1), expected: "20"},
+			{value: newBigInt("18446744073709551615"), expected: "1bffffffffffffffff"},
+			{value: newBigInt("-18446744073709551616"), expected: "3bffffffffffffffff"},
+			{value: newBigInt("18446744073709551616"), expected: "c249010000000000000000"},
+		}
+		for _, test := range tests {
+			t.Run(test.expected, func(t *testing.T) {
+				var writer bytes.Buffer
+				encoder := cbor.NewEncoder(&writer)
+				encoder.SetShortestIntEnabled(true)
+				if err := encoder.Encode(test.value); err != nil {
+					t.Fatal(err)
+				}
+				if encoded := hex.EncodeToString(writer.Bytes()); encoded != test.expected {
+					t.Errorf("%s != %s", encoded, test.expected)
+				}
+			})
+		}
+	})
+
+	t.Run("struct", func(t *testing.T) {
+		from := BignumStruct{
+			Amount:    *newBigInt("123456789012345678901234567890"),
+			AmountPtr: newBigInt("-123456789012345678901234567890"),
+			Small:     big.NewInt(1),
+		}
+		encoded, err := cbor.Marshal(from)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var to BignumStruct
+		if err := cbor.UnmarshalTo(encoded, &to); err != nil {
+			t.Fatal(err)
+		}
+		if to.Amount.Cmp(&from.Amount) != 0 || to.AmountPtr.Cmp(from.AmountPtr) != 0 || to.Small.Cmp(from.Small) != 0 {
+			t.Errorf("%+v != %+v", to, from)
+		}
+	})
+
+	t.Run("int", func(t *testing.T) {
+		from := BignumStruct{
+			Amount:    *newBigInt("18446744073709551615"),
+			AmountPtr: newBigInt("-9223372036854775808"),
+			Small:     big.NewInt(-128),
+		}
+		encoded, err := cbor.Marshal(from)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var to BignumIntStruct
+		if err := cbor.UnmarshalTo(encoded, &to); err != nil {
+			t.Fatal(err)
+		}
+		expected := BignumIntStruct{Amount: 18446744073709551615, AmountPtr: -9223372036854775808, Small: -128}
+		if to != expected {
+			t.Errorf("%+v != %+v", to, expected)
+		}
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		encoded, err := cbor.Marshal(newBigInt("18446744073709551616"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var v uint64
+		if err := cbor.UnmarshalTo(encoded, &v); err == nil {
+			t.Errorf("Expected overflow error: %v", v)
+		}
+		var i8 int8
+		encoded, err = cbor.Marshal(big.NewInt(128))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := cbor.UnmarshalTo(encoded, &i8); err == nil {
+			t.Errorf("Expected overflow error: %v", i8)
+		}
+	})
+}
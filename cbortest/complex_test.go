@@ -0,0 +1,107 @@
+// Copyright (C) 2022 The go-cbor Authors All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cbortest
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/cybergarage/go-cbor/cbor"
+)
+
+type ComplexStruct struct {
+	C64  complex64
+	C128 complex128
+	Cs   []complex64
+}
+
+func TestComplex(t *testing.T) {
+	tests := []struct {
+		value    any
+		expected string
+	}{
+		{value: complex64(1 + 2i), expected: "d9a7f882fa3f800000fa40000000"},
+		{value: complex128(1.5 - 0.5i), expected: "d9a7f882fb3ff8000000000000fbbfe0000000000000"},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%T/%v", test.value, test.value), func(t *testing.T) {
+			encoded, err := cbor.Marshal(test.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(encoded) != test.expected {
+				t.Errorf("%s != %s", hex.EncodeToString(encoded), test.expected)
+			}
+			decoded, err := cbor.Unmarshal(encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v, ok := decoded.(complex128); !ok || v != reflect.ValueOf(test.value).Complex() {
+				t.Errorf("%v (%T) != %v", decoded, decoded, test.value)
+			}
+			to := reflect.New(reflect.TypeOf(test.value))
+			if err := cbor.UnmarshalTo(encoded, to.Interface()); err != nil {
+				t.Fatal(err)
+			}
+			if to.Elem().Interface() != test.value {
+				t.Errorf("%v != %v", to.Elem().Interface(), test.value)
+			}
+		})
+	}
+
+	t.Run("shortest", func(t *testing.T) {
+		var writer bytes.Buffer
+		encoder := cbor.NewEncoder(&writer)
+		encoder.SetShortestFloatEnabled(true)
+		if err := encoder.Encode(complex128(1 + 2i)); err != nil {
+			t.Fatal(err)
+		}
+		if expected := "d9a7f882f93c00f94000"; hex.EncodeToString(writer.Bytes()) != expected {
+			t.Errorf("%s != %s", hex.EncodeToString(writer.Bytes()), expected)
+		}
+	})
+
+	t.Run("struct", func(t *testing.T) {
+		from := ComplexStruct{C64: 1 + 2i, C128: -3.25 + 4i, Cs: []complex64{5i, 6}}
+		encoded, err := cbor.Marshal(from)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var to ComplexStruct
+		if err := cbor.UnmarshalTo(encoded, &to); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(from, to) {
+			t.Errorf("%+v != %+v", to, from)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		// 43000([1]), 43000(["a", "b"])
+		for _, test := range []string{"d9a7f88101", "d9a7f88261616162"} {
+			encoded, err := hex.DecodeString(test)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := cbor.Unmarshal(encoded); !errors.Is(err, cbor.ErrDecode) {
+				t.Errorf("Expected decode error for %s: %v", test, err)
+			}
+		}
+	})
+}
@@ -36,4 +36,81 @@ func TestConfig(t *testing.T) {
 	if config.IsMapSortEnabled() {
 		t.Error("config.IsMapSortEnabled() must be false after setting to false")
 	}
+
+	// Test SetShortestIntEnabled and IsShortestIntEnabled
+	if config.IsShortestIntEnabled() {
+		t.Error("config.IsShortestIntEnabled() must be false")
+	}
+	config.SetShortestIntEnabled(true)
+	if !config.IsShortestIntEnabled() {
+		t.Error("config.IsShortestIntEnabled() must be true after setting to true")
+	}
+	config.SetShortestIntEnabled(false)
+	if config.IsShortestIntEnabled() {
+		t.Error("config.IsShortestIntEnabled() must be false after setting to false")
+	}
+
+	// Test SetShortestFloatEnabled and IsShortestFloatEnabled
+	if config.IsShortestFloatEnabled() {
+		t.Error("config.IsShortestFloatEnabled() must be false")
+	}
+	config.SetShortestFloatEnabled(true)
+	if !config.IsShortestFloatEnabled() {
+		t.Error("config.IsShortestFloatEnabled() must be true after setting to true")
+	}
+
+	// Test SetNaNCanonicalEnabled and IsNaNCanonicalEnabled
+	if config.IsNaNCanonicalEnabled() {
+		t.Error("config.IsNaNCanonicalEnabled() must be false")
+	}
+	config.SetNaNCanonicalEnabled(true)
+	if !config.IsNaNCanonicalEnabled() {
+		t.Error("config.IsNaNCanonicalEnabled() must be true after setting to true")
+	}
+
+	// Test SetMapSortMode and GetMapSortMode
+	if config.GetMapSortMode() != cbor.MapSortNone {
+		t.Error("config.GetMapSortMode() must be MapSortNone")
+	}
+	config.SetMapSortMode(cbor.MapSortBytewise)
+	if config.GetMapSortMode() != cbor.MapSortBytewise {
+		t.Error("config.GetMapSortMode() must be MapSortBytewise after setting to MapSortBytewise")
+	}
+
+	// Test SetDeterministicEnabled and IsDeterministicEnabled
+	if config.IsDeterministicEnabled() {
+		t.Error("config.IsDeterministicEnabled() must be false")
+	}
+	config.SetDeterministicEnabled(true)
+	if !config.IsDeterministicEnabled() {
+		t.Error("config.IsDeterministicEnabled() must be true after setting to true")
+	}
+
+	// Test SetTimeMode and GetTimeMode
+	if config.GetTimeMode() != cbor.TimeRFC3339 {
+		t.Error("config.GetTimeMode() must be TimeRFC3339")
+	}
+	config.SetTimeMode(cbor.TimeUnix)
+	if config.GetTimeMode() != cbor.TimeUnix {
+		t.Error("config.GetTimeMode() must be TimeUnix after setting to TimeUnix")
+	}
+
+	// Test SetTagSet and GetTagSet
+	if config.GetTagSet() != nil {
+		t.Error("config.GetTagSet() must be nil")
+	}
+	ts := cbor.NewTagSet()
+	config.SetTagSet(ts)
+	if config.GetTagSet() != ts {
+		t.Error("config.GetTagSet() must be the set tag set")
+	}
+
+	// Test SetUndefinedMode and GetUndefinedMode
+	if config.GetUndefinedMode() != cbor.UndefinedZero {
+		t.Error("config.GetUndefinedMode() must be UndefinedZero")
+	}
+	config.SetUndefinedMode(cbor.UndefinedError)
+	if config.GetUndefinedMode() != cbor.UndefinedError {
+		t.Error("config.GetUndefinedMode() must be UndefinedError after setting to UndefinedError")
+	}
 }
@@ -19,6 +19,9 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
+	"math"
+	"math/big"
 	"testing"
 	"time"
 
@@ -71,9 +74,9 @@ func TestDecoder(t *testing.T) {
 				{encoded: "1a000f4240", expected: int32(1000000)},
 				{encoded: "1b000000e8d4a51000", expected: int64(1000000000000)},
 				{encoded: "1bffffffffffffffff", expected: uint64(18446744073709551615)},
-				// {encoded: "c249010000000000000000", expected: uint64(18446744073709551616)},
-				// {encoded: "3bffffffffffffffff", expected: int64(-18446744073709551616)},
-				// {encoded: "c349010000000000000000", expected: int64(-18446744073709551617)},
+				{encoded: "c249010000000000000000", expected: newBigInt("18446744073709551616")},
+				{encoded: "3bffffffffffffffff", expected: newBigInt("-18446744073709551616")},
+				{encoded: "c349010000000000000000", expected: newBigInt("-18446744073709551617")},
 				{encoded: "20", expected: int8(-1)},
 				{encoded: "29", expected: int8(-10)},
 				{encoded: "3863", expected: int8(-100)},
@@ -104,6 +107,8 @@ func TestDecoder(t *testing.T) {
 				{encoded: "f5", expected: true},
 				{encoded: "f6", expected: nil},
 				{encoded: "c074323031332d30332d32315432303a30343a30305a", expected: t20120321},
+				{encoded: "c11a514b67b0", expected: t20120321},
+				{encoded: "c1fb41d452d9ec200000", expected: t20120321.Add(500 * time.Millisecond)},
 				{encoded: "60", expected: ""},
 				{encoded: "6161", expected: "a"},
 				{encoded: "6449455446", expected: "IETF"},
@@ -117,6 +122,17 @@ func TestDecoder(t *testing.T) {
 				{encoded: "a0", expected: map[any]any{}},
 				{encoded: "a201020304", expected: map[any]any{1: 2, 3: 4}},
 				{encoded: "a56161614161626142616361436164614461656145", expected: map[any]any{"a": "A", "b": "B", "c": "C", "d": "D", "e": "E"}},
+				{encoded: "5f42010243030405ff", expected: []byte{0x01, 0x02, 0x03, 0x04, 0x05}},
+				{encoded: "7f657374726561646d696e67ff", expected: "streaming"},
+				{encoded: "9fff", expected: []int8{}},
+				{encoded: "9f018202039f0405ffff", expected: []any{1, []any{2, 3}, []any{4, 5}}},
+				{encoded: "9f01820203820405ff", expected: []any{1, []any{2, 3}, []any{4, 5}}},
+				{encoded: "83018202039f0405ff", expected: []any{1, []any{2, 3}, []any{4, 5}}},
+				{encoded: "83019f0203ff820405", expected: []any{1, []any{2, 3}, []any{4, 5}}},
+				{encoded: "9f0102030405060708090a0b0c0d0e0f101112131415161718181819ff", expected: []int8{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25}},
+				{encoded: "bf61610161629f0203ffff", expected: map[any]any{"a": 1, "b": []any{2, 3}}},
+				{encoded: "826161bf61626163ff", expected: []any{"a", map[any]any{"b": "c"}}},
+				{encoded: "bf6346756ef563416d7421ff", expected: map[any]any{"Fun": true, "Amt": -2}},
 			}
 			for _, test := range tests {
 				t.Run(fmt.Sprintf("%T/%s=>%v", test.expected, test.encoded, test.expected), func(t *testing.T) {
@@ -142,3 +158,312 @@ func TestDecoder(t *testing.T) {
 		})
 	})
 }
+
+func TestFloat16Decoder(t *testing.T) {
+	tests := []struct {
+		encoded  string
+		expected float64
+	}{
+		{encoded: "f93e00", expected: 1.5},
+		{encoded: "f90001", expected: 5.960464477539063e-8},
+		{encoded: "f903ff", expected: 0.00006097555160522461},
+		{encoded: "f97c00", expected: math.Inf(1)},
+		{encoded: "f9fc00", expected: math.Inf(-1)},
+		{encoded: "f97e00", expected: math.NaN()},
+	}
+	for _, test := range tests {
+		t.Run(test.encoded, func(t *testing.T) {
+			testBytes, err := hex.DecodeString(test.encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			v, err := cbor.Unmarshal(testBytes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f, ok := v.(float64)
+			if !ok {
+				t.Fatalf("%v (%T) is not float64", v, v)
+			}
+			if math.IsNaN(test.expected) {
+				if !math.IsNaN(f) {
+					t.Errorf("%v is not NaN", f)
+				}
+				return
+			}
+			if f != test.expected {
+				t.Errorf("%v != %v", f, test.expected)
+			}
+
+			var f32 float32
+			if err := cbor.UnmarshalTo(testBytes, &f32); err != nil {
+				t.Fatal(err)
+			}
+			if float64(f32) != test.expected {
+				t.Errorf("%v != %v", f32, test.expected)
+			}
+		})
+	}
+}
+
+func TestFloat16Unmarshal(t *testing.T) {
+	from := struct {
+		Value cbor.Float16
+	}{
+		Value: 0.5,
+	}
+	encoded, err := cbor.Marshal(from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := struct {
+		Value cbor.Float16
+	}{}
+	if err := cbor.UnmarshalTo(encoded, &to); err != nil {
+		t.Fatal(err)
+	}
+	if to != from {
+		t.Errorf("%v != %v", to, from)
+	}
+}
+
+func TestIndefiniteDecoderErrors(t *testing.T) {
+	tests := []string{
+		"5f6161ff",     // text string chunk in byte string
+		"7f4161ff",     // byte string chunk in text string
+		"5f5f4101ffff", // nested indefinite-length chunk
+		"5f4101",       // missing break
+		"9f01",         // missing break
+		"bf0102",       // missing break
+		"bf01ff",       // break as map value
+		"81ff",         // break in definite-length array
+		"9f81ffff",     // break in definite-length array in indefinite-length array
+		"c0ff",         // break as tag content
+		"ff",           // break without indefinite-length item
+	}
+	for _, test := range tests {
+		t.Run(test, func(t *testing.T) {
+			testBytes, err := hex.DecodeString(test)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v, err := cbor.Unmarshal(testBytes); err == nil {
+				t.Errorf("Expected error for %s: %v", test, v)
+			}
+		})
+	}
+}
+
+func TestIndefiniteUnmarshal(t *testing.T) {
+	// {_ "Name": "a", "Tags": [_ "x", "y"], "Data": (_ h'01', h'02')}
+	testBytes, err := hex.DecodeString("bf644e616d65616164546167739f61786179ff64446174615f41014102ffff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var to struct {
+		Name string
+		Tags []string
+		Data []byte
+	}
+	if err := cbor.UnmarshalTo(testBytes, &to); err != nil {
+		t.Fatal(err)
+	}
+	if to.Name != "a" || len(to.Tags) != 2 || to.Tags[0] != "x" || to.Tags[1] != "y" || !bytes.Equal(to.Data, []byte{0x01, 0x02}) {
+		t.Errorf("%+v", to)
+	}
+}
+
+func TestDateTimeDecoderErrors(t *testing.T) {
+	tests := []string{
+		"c16161",                   // 1("a")
+		"c1fb7ff8000000000000",     // 1(NaN)
+		"c11bffffffffffffffff",     // 1(18446744073709551615)
+		"d903e9a0",                 // 1001({})
+		"d903e9a2011a514b67b00201", // 1001({1: 1363896240, 2: 1})
+	}
+	for _, test := range tests {
+		t.Run(test, func(t *testing.T) {
+			encoded, err := hex.DecodeString(test)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := cbor.Unmarshal(encoded); !errors.Is(err, cbor.ErrDecode) {
+				t.Errorf("Expected decode error: %v", err)
+			}
+		})
+	}
+
+	// Lengths within int are not allocated before reading the short data.
+	tests = []string{
+		"5b7fffffffffffffff",
+		"7b7fffffffffffffff",
+		"5a7fffffff00",
+		"7f7b7fffffffffffffffff",
+	}
+	for _, test := range tests {
+		t.Run(test, func(t *testing.T) {
+			encoded, err := hex.DecodeString(test)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := cbor.Unmarshal(encoded); !errors.Is(err, io.ErrUnexpectedEOF) {
+				t.Errorf("Expected unexpected EOF error: %v", err)
+			}
+		})
+	}
+}
+
+func TestExtendedTimeDecoder(t *testing.T) {
+	t20120321, err := time.Parse(time.RFC3339, "2013-03-21T20:04:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		encoded  string
+		expected time.Time
+	}{
+		{encoded: "d903e9a1011a514b67b0", expected: t20120321},
+		{encoded: "d903e9a2011a514b67b0221901f4", expected: t20120321.Add(500 * time.Millisecond)},
+		{encoded: "d903e9a2011a514b67b0251901f4", expected: t20120321.Add(500 * time.Microsecond)},
+		{encoded: "d903e9a2011a514b67b0281901f4", expected: t20120321.Add(500 * time.Nanosecond)},
+		{encoded: "d903e9a2011a514b67b0376b4e6f6e65786973742d545a", expected: t20120321}, // unknown elective key
+	}
+	for _, test := range tests {
+		t.Run(test.encoded, func(t *testing.T) {
+			encoded, err := hex.DecodeString(test.encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := cbor.Unmarshal(encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v, ok := decoded.(time.Time); !ok || !v.Equal(test.expected) {
+				t.Errorf("%v != %v", decoded, test.expected)
+			}
+		})
+	}
+}
+
+func TestNegativeIntDecoder(t *testing.T) {
+	tests := []struct {
+		encoded  string
+		expected any
+	}{
+		{encoded: "387f", expected: int8(-128)},
+		{encoded: "38ff", expected: int16(-256)},
+		{encoded: "397fff", expected: int16(-32768)},
+		{encoded: "39ffff", expected: int32(-65536)},
+		{encoded: "3a7fffffff", expected: int32(-2147483648)},
+		{encoded: "3affffffff", expected: int64(-4294967296)},
+		{encoded: "3b7fffffffffffffff", expected: int64(math.MinInt64)},
+		{encoded: "3b8000000000000000", expected: newBigInt("-9223372036854775809")},
+	}
+	for _, test := range tests {
+		t.Run(test.encoded, func(t *testing.T) {
+			encoded, err := hex.DecodeString(test.encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := cbor.Unmarshal(encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := deepEqual(decoded, test.expected); err != nil {
+				t.Errorf("%v (%T) != %v (%T)", decoded, decoded, test.expected, test.expected)
+			}
+		})
+	}
+
+	t.Run("unmarshal", func(t *testing.T) {
+		encoded, err := hex.DecodeString("3bffffffffffffffff")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var i64 int64
+		if err := cbor.UnmarshalTo(encoded, &i64); err == nil {
+			t.Errorf("Expected overflow error: %v", i64)
+		}
+		var n big.Int
+		if err := cbor.UnmarshalTo(encoded, &n); err != nil {
+			t.Fatal(err)
+		}
+		if n.Cmp(newBigInt("-18446744073709551616")) != 0 {
+			t.Errorf("%v != %v", &n, "-18446744073709551616")
+		}
+	})
+}
+
+func TestNumberOfItemsOverflowDecoder(t *testing.T) {
+	tests := []string{
+		"5bffffffffffffffff",
+		"7bffffffffffffffff",
+		"9bffffffffffffffff",
+		"bbffffffffffffffff",
+	}
+	for _, test := range tests {
+		t.Run(test, func(t *testing.T) {
+			encoded, err := hex.DecodeString(test)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := cbor.Unmarshal(encoded); !errors.Is(err, cbor.ErrDecode) {
+				t.Errorf("Expected decode error: %v", err)
+			}
+		})
+	}
+}
+
+func TestIncomparableMapKeyDecoder(t *testing.T) {
+	tests := []string{
+		"a1810101",       // {[1]: 1}
+		"a1a1010201",     // {{1: 2}: 1}
+		"a1d8ff82010201", // {255([1, 2]): 1}
+		"a1d8ffa1010201", // {255({1: 2}): 1}
+	}
+	for _, test := range tests {
+		t.Run(test, func(t *testing.T) {
+			encoded, err := hex.DecodeString(test)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := cbor.Unmarshal(encoded); !errors.Is(err, cbor.ErrDecode) {
+				t.Errorf("Expected decode error for an incomparable map key: %v", err)
+			}
+		})
+	}
+}
+
+func TestNaNPayloadDecoder(t *testing.T) {
+	tests := []struct {
+		encoded  string
+		expected any
+	}{
+		{encoded: "f97d00", expected: uint64(0x7ff4000000000000)},
+		{encoded: "f9fd01", expected: uint64(0xfff4040000000000)},
+		{encoded: "fa7f800001", expected: uint32(0x7f800001)},
+		{encoded: "fb7ff0000000000001", expected: uint64(0x7ff0000000000001)},
+	}
+	for _, test := range tests {
+		t.Run(test.encoded, func(t *testing.T) {
+			encoded, err := hex.DecodeString(test.encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := cbor.Unmarshal(encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var bits any
+			switch v := decoded.(type) {
+			case float32:
+				bits = math.Float32bits(v)
+			case float64:
+				bits = math.Float64bits(v)
+			}
+			if bits != test.expected {
+				t.Errorf("%x != %x", bits, test.expected)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"encoding/hex"
 	"fmt"
+	"math"
 	"testing"
 	"time"
 
@@ -67,25 +68,25 @@ func TestEncoder(t *testing.T) {
 				{value: uint64(18446744073709551615), expected: "1bffffffffffffffff"},
 				{value: uint(1000000000000), expected: "1b000000e8d4a51000"},
 				{value: uint(18446744073709551615), expected: "1bffffffffffffffff"},
-				// {value: uint(18446744073709551616), expected: "c249010000000000000000"},
+				{value: newBigInt("18446744073709551616"), expected: "c249010000000000000000"},
 				// {value: int(-18446744073709551616), expected: "3bffffffffffffffff"},
-				// {value: int(-18446744073709551617), expected: "c349010000000000000000"},
+				{value: newBigInt("-18446744073709551617"), expected: "c349010000000000000000"},
 				{value: int8(-1), expected: "20"},
 				{value: int8(-10), expected: "29"},
 				{value: int8(-100), expected: "3863"},
 				{value: int16(-1000), expected: "3903e7"},
-				// {value: float16(0.0), expected: "f90000"},
-				// {value: float16(0.0), expected: "f98000"},
-				// {value: float16(1.0), expected: "f93c00"},
+				{value: cbor.Float16(0.0), expected: "f90000"},
+				{value: cbor.Float16(math.Copysign(0, -1)), expected: "f98000"},
+				{value: cbor.Float16(1.0), expected: "f93c00"},
 				{value: float64(1.1), expected: "fb3ff199999999999a"},
-				// {value: float16(1.5), expected: "f93e00"},
-				// {value: float16(65504.0), expected: "f97bff"},
+				{value: cbor.Float16(1.5), expected: "f93e00"},
+				{value: cbor.Float16(65504.0), expected: "f97bff"},
 				{value: float32(100000.0), expected: "fa47c35000"},
 				// {value: float64(3.4028234663852886e+38), expected: "fa7f7fffff"},
 				{value: float64(1.0e+300), expected: "fb7e37e43c8800759c"},
-				// {value: float16(5.960464477539063e-8), expected: "f90001"},
-				// {value: float16(0.00006103515625), expected: "f90400"},
-				// {value: float16(-4.0), expected: "f9c400"},
+				{value: cbor.Float16(5.960464477539063e-8), expected: "f90001"},
+				{value: cbor.Float16(0.00006103515625), expected: "f90400"},
+				{value: cbor.Float16(-4.0), expected: "f9c400"},
 				{value: float64(-4.1), expected: "fbc010666666666666"},
 				// {value: float64(math.Inf), expected: "f97c00"},
 				// {value: float64(math.NaN), expected: "f97e00"},
@@ -138,3 +139,362 @@ func TestEncoder(t *testing.T) {
 		})
 	})
 }
+
+func TestShortestIntEncoder(t *testing.T) {
+	tests := []struct {
+		value    any
+		expected string
+	}{
+		{value: uint8(1), expected: "01"},
+		{value: uint16(1), expected: "01"},
+		{value: uint32(24), expected: "1818"},
+		{value: uint64(255), expected: "18ff"},
+		{value: uint(256), expected: "190100"},
+		{value: uint64(65535), expected: "19ffff"},
+		{value: uint64(65536), expected: "1a00010000"},
+		{value: uint64(4294967295), expected: "1affffffff"},
+		{value: uint64(4294967296), expected: "1b0000000100000000"},
+		{value: int8(-1), expected: "20"},
+		{value: int16(-24), expected: "37"},
+		{value: int32(-25), expected: "3818"},
+		{value: int64(5), expected: "05"},
+		{value: int64(-1000), expected: "3903e7"},
+		{value: int(math.MinInt64), expected: "3b7fffffffffffffff"},
+		{value: []int{1, 1000}, expected: "82011903e8"},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%T/%v=>%s", test.value, test.value, test.expected), func(t *testing.T) {
+			var writer bytes.Buffer
+			encoder := cbor.NewEncoder(&writer)
+			encoder.SetShortestIntEnabled(true)
+			if err := encoder.Encode(test.value); err != nil {
+				t.Fatal(err)
+			}
+			encoded := hex.EncodeToString(writer.Bytes())
+			if encoded != test.expected {
+				t.Errorf("%s != %s", encoded, test.expected)
+			}
+		})
+	}
+}
+
+func TestLengthEncoder(t *testing.T) {
+	tests := []struct {
+		length   int
+		expected string
+	}{
+		{length: 23, expected: "57"},
+		{length: 24, expected: "5818"},
+		{length: 255, expected: "58ff"},
+		{length: 256, expected: "590100"},
+		{length: 65535, expected: "59ffff"},
+		{length: 65536, expected: "5a00010000"},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%d=>%s", test.length, test.expected), func(t *testing.T) {
+			encoded, err := cbor.Marshal(make([]byte, test.length))
+			if err != nil {
+				t.Fatal(err)
+			}
+			header := hex.EncodeToString(encoded[:len(encoded)-test.length])
+			if header != test.expected {
+				t.Errorf("%s != %s", header, test.expected)
+			}
+		})
+	}
+}
+
+func TestShortestFloatEncoder(t *testing.T) {
+	tests := []struct {
+		value        any
+		nanCanonical bool
+		expected     string
+	}{
+		{value: float64(0.0), expected: "f90000"},
+		{value: math.Copysign(0, -1), expected: "f98000"},
+		{value: float64(1.0), expected: "f93c00"},
+		{value: float64(1.1), expected: "fb3ff199999999999a"},
+		{value: float64(1.5), expected: "f93e00"},
+		{value: float64(65504.0), expected: "f97bff"},
+		{value: float64(100000.0), expected: "fa47c35000"},
+		{value: float64(3.4028234663852886e+38), expected: "fa7f7fffff"},
+		{value: float64(1.0e+300), expected: "fb7e37e43c8800759c"},
+		{value: float64(5.960464477539063e-8), expected: "f90001"},
+		{value: float64(0.00006103515625), expected: "f90400"},
+		{value: float64(-4.0), expected: "f9c400"},
+		{value: float64(-4.1), expected: "fbc010666666666666"},
+		{value: float32(0.5), expected: "f93800"},
+		{value: float32(21.0), expected: "f94d40"},
+		{value: float32(100000.0), expected: "fa47c35000"},
+		{value: cbor.Float16(1.5), expected: "f93e00"},
+		{value: math.Inf(1), expected: "f97c00"},
+		{value: math.Inf(-1), expected: "f9fc00"},
+		{value: float32(math.Inf(1)), expected: "f97c00"},
+		{value: math.Float64frombits(0x7ff8000000000000), expected: "f97e00"},
+		{value: math.Float64frombits(0x7ff8000000000001), expected: "fb7ff8000000000001"},
+		{value: math.Float64frombits(0x7ff8000000000001), nanCanonical: true, expected: "f97e00"},
+		{value: math.Float32frombits(0x7fc00001), nanCanonical: true, expected: "f97e00"},
+		{value: math.Float32frombits(0x7f800001), expected: "fa7f800001"},
+		{value: math.Float32frombits(0x7fa00000), expected: "f97d00"},
+		{value: math.Float64frombits(0x7ff0000000000001), expected: "fb7ff0000000000001"},
+		{value: math.Float64frombits(0xfff4000000000000), expected: "f9fd00"},
+		{value: math.Float64frombits(0x7ff0000020000000), expected: "fa7f800001"},
+		{value: cbor.NewFloat16FromBits(0x7d00), expected: "f97d00"},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%T/%v=>%s", test.value, test.value, test.expected), func(t *testing.T) {
+			var writer bytes.Buffer
+			encoder := cbor.NewEncoder(&writer)
+			encoder.SetShortestFloatEnabled(true)
+			encoder.SetNaNCanonicalEnabled(test.nanCanonical)
+			if err := encoder.Encode(test.value); err != nil {
+				t.Fatal(err)
+			}
+			encoded := hex.EncodeToString(writer.Bytes())
+			if encoded != test.expected {
+				t.Errorf("%s != %s", encoded, test.expected)
+			}
+		})
+	}
+}
+
+func TestNaNCanonicalEncoder(t *testing.T) {
+	tests := []struct {
+		value    any
+		expected string
+	}{
+		{value: math.Float64frombits(0x7ff8000000000001), expected: "fb7ff8000000000000"},
+		{value: math.Float32frombits(0x7fc00001), expected: "fa7fc00000"},
+		{value: math.Inf(1), expected: "fb7ff0000000000000"},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%T/%v=>%s", test.value, test.value, test.expected), func(t *testing.T) {
+			var writer bytes.Buffer
+			encoder := cbor.NewEncoder(&writer)
+			encoder.SetNaNCanonicalEnabled(true)
+			if err := encoder.Encode(test.value); err != nil {
+				t.Fatal(err)
+			}
+			encoded := hex.EncodeToString(writer.Bytes())
+			if encoded != test.expected {
+				t.Errorf("%s != %s", encoded, test.expected)
+			}
+		})
+	}
+}
+
+func TestNaNPayloadEncoder(t *testing.T) {
+	tests := []struct {
+		value    any
+		expected string
+	}{
+		{value: math.Float32frombits(0x7f800001), expected: "fa7f800001"},
+		{value: math.Float32frombits(0xffa00000), expected: "faffa00000"},
+		{value: math.Float64frombits(0x7ff0000000000001), expected: "fb7ff0000000000001"},
+		{value: cbor.NewFloat16FromBits(0x7d01), expected: "f97d01"},
+	}
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			encoded, err := cbor.Marshal(test.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(encoded) != test.expected {
+				t.Errorf("%s != %s", hex.EncodeToString(encoded), test.expected)
+			}
+		})
+	}
+}
+
+func TestIndefiniteEncoder(t *testing.T) {
+	var writer bytes.Buffer
+	encoder := cbor.NewEncoder(&writer)
+	steps := []func() error{
+		encoder.BeginMap,
+		func() error { return encoder.Encode("a") },
+		encoder.BeginArray,
+		func() error { return encoder.Encode(uint8(1)) },
+		func() error { return encoder.Encode(uint8(2)) },
+		encoder.End,
+		func() error { return encoder.Encode("b") },
+		encoder.BeginBytes,
+		func() error { return encoder.Encode([]byte{0x01, 0x02}) },
+		func() error { return encoder.Encode([]byte{0x03}) },
+		encoder.End,
+		func() error { return encoder.Encode("c") },
+		encoder.BeginText,
+		func() error { return encoder.Encode("strea") },
+		func() error { return encoder.Encode("ming") },
+		encoder.End,
+		encoder.End,
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := "bf61619f0102ff61625f4201024103ff61637f657374726561646d696e67ffff"
+	if encoded := hex.EncodeToString(writer.Bytes()); encoded != expected {
+		t.Errorf("%s != %s", encoded, expected)
+	}
+
+	decoded, err := cbor.Unmarshal(writer.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = deepEqual(decoded, map[any]any{"a": []any{1, 2}, "b": []byte{0x01, 0x02, 0x03}, "c": "streaming"})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestIndefiniteEncoderErrors(t *testing.T) {
+	var writer bytes.Buffer
+	encoder := cbor.NewEncoder(&writer)
+	if err := encoder.End(); err == nil {
+		t.Error("Expected error when ending without beginning")
+	}
+
+	if err := encoder.BeginBytes(); err != nil {
+		t.Fatal(err)
+	}
+	if err := encoder.Encode("text"); err == nil {
+		t.Error("Expected error when encoding a text string chunk in a byte string")
+	}
+	if err := encoder.Encode(1); err == nil {
+		t.Error("Expected error when encoding an integer chunk in a byte string")
+	}
+	if err := encoder.BeginBytes(); err == nil {
+		t.Error("Expected error when nesting an indefinite-length chunk")
+	}
+	if err := encoder.End(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := encoder.BeginText(); err != nil {
+		t.Fatal(err)
+	}
+	if err := encoder.Encode([]byte("bytes")); err == nil {
+		t.Error("Expected error when encoding a byte string chunk in a text string")
+	}
+}
+
+func TestDeterministicEncoder(t *testing.T) {
+	type deterministicStruct struct {
+		BB int
+		A  int
+	}
+	tests := []struct {
+		value    any
+		expected string
+	}{
+		{value: map[int]int{10: 1, 9: 2}, expected: "a209020a01"},
+		{value: map[any]any{"a": 2, cbor.ByteString("a"): 1}, expected: "a2416101616102"},
+		{value: map[any]any{100: 1, -1: 2, "z": 3, "aa": 4, false: 5}, expected: "a51864012002617a0362616104f405"},
+		{value: deterministicStruct{BB: 1, A: 2}, expected: "a261410262424201"},
+		{value: 1.5, expected: "f93e00"},
+		{value: uint64(1000), expected: "1903e8"},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%v=>%s", test.value, test.expected), func(t *testing.T) {
+			var writer bytes.Buffer
+			encoder := cbor.NewEncoder(&writer)
+			encoder.SetDeterministicEnabled(true)
+			if err := encoder.Encode(test.value); err != nil {
+				t.Fatal(err)
+			}
+			encoded := hex.EncodeToString(writer.Bytes())
+			if encoded != test.expected {
+				t.Errorf("%s != %s", encoded, test.expected)
+			}
+		})
+	}
+}
+
+func TestDeterministicEncoderErrors(t *testing.T) {
+	var writer bytes.Buffer
+	encoder := cbor.NewEncoder(&writer)
+	encoder.SetDeterministicEnabled(true)
+	if err := encoder.Encode(map[any]any{int8(1): 1, int16(1): 2}); err == nil {
+		t.Error("Expected error when encoding duplicate map keys")
+	}
+	if err := encoder.BeginArray(); err == nil {
+		t.Error("Expected error when beginning an indefinite-length item")
+	}
+}
+
+func TestMapSortModeEncoder(t *testing.T) {
+	value := map[any]any{100: 1, -1: 2, "z": 3, "aa": 4, false: 5}
+	tests := []struct {
+		mode     cbor.MapSortMode
+		expected string
+	}{
+		{mode: cbor.MapSortBytewise, expected: "a51864012002617a0362616104f405"},
+		{mode: cbor.MapSortLengthFirst, expected: "a52002f405186401617a0362616104"},
+	}
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			var writer bytes.Buffer
+			encoder := cbor.NewEncoder(&writer)
+			encoder.SetShortestIntEnabled(true)
+			encoder.SetMapSortMode(test.mode)
+			if err := encoder.Encode(value); err != nil {
+				t.Fatal(err)
+			}
+			encoded := hex.EncodeToString(writer.Bytes())
+			if encoded != test.expected {
+				t.Errorf("%s != %s", encoded, test.expected)
+			}
+		})
+	}
+}
+
+func TestTimeModeEncoder(t *testing.T) {
+	t20120321, err := time.Parse(time.RFC3339, "2013-03-21T20:04:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		mode     cbor.TimeMode
+		value    time.Time
+		expected string
+	}{
+		{mode: cbor.TimeRFC3339, value: t20120321, expected: "c074323031332d30332d32315432303a30343a30305a"},
+		{mode: cbor.TimeUnix, value: t20120321, expected: "c11a514b67b0"},
+		{mode: cbor.TimeUnix, value: time.Unix(-1, 0), expected: "c120"},
+		{mode: cbor.TimeRFC3339Nano, value: t20120321.Add(123456789), expected: "c0781e323031332d30332d32315432303a30343a30302e3132333435363738395a"},
+		{mode: cbor.TimeUnixFloat, value: t20120321.Add(500 * time.Millisecond), expected: "c1fb41d452d9ec200000"},
+		{mode: cbor.TimeUnixFloat, value: time.Date(2300, 1, 1, 0, 0, 0, 0, time.UTC), expected: "c1fb420365aed8000000"},
+		{mode: cbor.TimeUnixFloat, value: time.Date(1600, 1, 1, 0, 0, 0, 0, time.UTC), expected: "c1fbc205bf98b0000000"},
+		{mode: cbor.TimeExtended, value: t20120321, expected: "d903e9a1011a514b67b0"},
+		{mode: cbor.TimeExtended, value: t20120321.Add(123456789), expected: "d903e9a2011a514b67b0281a075bcd15"},
+		{mode: cbor.TimeExtended, value: t20120321.In(time.FixedZone("", 9*60*60)), expected: "d903e9a2011a514b67b029662b30393a3030"},
+	}
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			var writer bytes.Buffer
+			encoder := cbor.NewEncoder(&writer)
+			encoder.SetTimeMode(test.mode)
+			if err := encoder.Encode(test.value); err != nil {
+				t.Fatal(err)
+			}
+			encoded := hex.EncodeToString(writer.Bytes())
+			if encoded != test.expected {
+				t.Errorf("%s != %s", encoded, test.expected)
+			}
+			var decoded time.Time
+			if err := cbor.UnmarshalTo(writer.Bytes(), &decoded); err != nil {
+				t.Fatal(err)
+			}
+			if !decoded.Equal(test.value) {
+				t.Errorf("%v != %v", decoded, test.value)
+			}
+			_, decodedOffset := decoded.Zone()
+			_, offset := test.value.Zone()
+			if test.mode == cbor.TimeExtended && decodedOffset != offset {
+				t.Errorf("%v != %v", decoded.Location(), test.value.Location())
+			}
+		})
+	}
+}
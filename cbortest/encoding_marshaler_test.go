@@ -0,0 +1,158 @@
+// Copyright (C) 2022 The go-cbor Authors All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cbortest
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/netip"
+	"reflect"
+	"testing"
+
+	"github.com/cybergarage/go-cbor/cbor"
+)
+
+// TextColor is encoded as a text string with encoding.TextMarshaler.
+type TextColor int
+
+func (c TextColor) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("color-%d", c)), nil
+}
+
+func (c *TextColor) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(string(text), "color-%d", c)
+	return err
+}
+
+type EncodingMarshalerStruct struct {
+	Addr   netip.Addr
+	Color  TextColor
+	Routes map[netip.Addr]TextColor
+}
+
+func TestEncodingMarshaler(t *testing.T) {
+	addr := netip.MustParseAddr("192.168.0.1")
+
+	t.Run("encode", func(t *testing.T) {
+		tests := []struct {
+			value    any
+			expected string
+		}{
+			{value: addr, expected: "44c0a80001"},
+			{value: TextColor(1), expected: "67636f6c6f722d31"},
+		}
+		for _, test := range tests {
+			t.Run(fmt.Sprintf("%T", test.value), func(t *testing.T) {
+				encoded, err := cbor.Marshal(test.value)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if hex.EncodeToString(encoded) != test.expected {
+					t.Errorf("%s != %s", hex.EncodeToString(encoded), test.expected)
+				}
+			})
+		}
+	})
+
+	t.Run("unmarshal", func(t *testing.T) {
+		encoded, err := cbor.Marshal(addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var toAddr netip.Addr
+		if err := cbor.UnmarshalTo(encoded, &toAddr); err != nil {
+			t.Fatal(err)
+		}
+		if toAddr != addr {
+			t.Errorf("%v != %v", toAddr, addr)
+		}
+
+		encoded, err = cbor.Marshal(addr.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		toAddr = netip.Addr{}
+		if err := cbor.UnmarshalTo(encoded, &toAddr); err != nil {
+			t.Fatal(err)
+		}
+		if toAddr != addr {
+			t.Errorf("%v != %v", toAddr, addr)
+		}
+	})
+
+	t.Run("struct", func(t *testing.T) {
+		from := EncodingMarshalerStruct{
+			Addr:   addr,
+			Color:  2,
+			Routes: map[netip.Addr]TextColor{addr: 3},
+		}
+		encoded, err := cbor.Marshal(from)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var to EncodingMarshalerStruct
+		if err := cbor.UnmarshalTo(encoded, &to); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(from, to) {
+			t.Errorf("%+v != %+v", to, from)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		encoded, err := cbor.Marshal([]byte{0x01, 0x02})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var toAddr netip.Addr
+		if err := cbor.UnmarshalTo(encoded, &toAddr); err == nil {
+			t.Error("Expected error from UnmarshalBinary")
+		}
+	})
+}
+
+func TestByteStringMapKey(t *testing.T) {
+	// {h'0102': 1}
+	encoded, err := hex.DecodeString("a142010201")
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := cbor.Unmarshal(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := cbor.ByteString([]byte{0x01, 0x02})
+	if v, ok := decoded.(map[any]any)[key]; !ok || v != int8(1) {
+		t.Errorf("%v is not found in %v", key, decoded)
+	}
+	reencoded, err := cbor.Marshal(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(encoded, reencoded) {
+		t.Errorf("%s != %s", hex.EncodeToString(reencoded), hex.EncodeToString(encoded))
+	}
+
+	// {[1]: 1}
+	encoded, err = hex.DecodeString("a1810101")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cbor.Unmarshal(encoded); !errors.Is(err, cbor.ErrDecode) {
+		t.Errorf("Expected decode error for an incomparable map key: %v", err)
+	}
+}
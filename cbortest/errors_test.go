@@ -15,6 +15,7 @@
 package cbortest
 
 import (
+	"math/big"
 	"strings"
 	"testing"
 
@@ -41,9 +42,8 @@ func TestErrors(t *testing.T) {
 		{
 			name: "NotSupportedNativeType",
 			testFunc: func() error {
-				// Trigger by encoding a cmplex type
-				cmplex := complex(1, 2)
-				_, err := cbor.Marshal(cmplex)
+				// Trigger by encoding a channel type
+				_, err := cbor.Marshal(make(chan int))
 				return err
 			},
 			contains: "not supported",
@@ -70,6 +70,32 @@ func TestErrors(t *testing.T) {
 			},
 			contains: "unmarshal error",
 		},
+		{
+			name: "UnmarshalNilDestination",
+			testFunc: func() error {
+				// Trigger by unmarshaling to nil
+				return cbor.UnmarshalTo([]byte{0x01}, nil)
+			},
+			contains: "cast error",
+		},
+		{
+			name: "UnmarshalBignumNilDestination",
+			testFunc: func() error {
+				// Trigger by unmarshaling a bignum to nil
+				data, _ := cbor.Marshal(new(big.Int).Lsh(big.NewInt(1), 64))
+				return cbor.UnmarshalTo(data, nil)
+			},
+			contains: "unmarshal error",
+		},
+		{
+			name: "UnmarshalRationalNilDestination",
+			testFunc: func() error {
+				// Trigger by unmarshaling a rational number to nil
+				data, _ := cbor.Marshal(big.NewRat(1, 3))
+				return cbor.UnmarshalTo(data, nil)
+			},
+			contains: "unmarshal error",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,162 @@
+// Copyright (C) 2022 The go-cbor Authors All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cbortest
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/cybergarage/go-cbor/cbor"
+)
+
+type FractionStruct struct {
+	Price cbor.DecimalFraction
+	Ratio cbor.BigFloat
+}
+
+func TestFraction(t *testing.T) {
+	tests := []struct {
+		value    any
+		encoded  string
+		expected string
+	}{
+		// RFC 8949: 3.4.4. Decimal Fractions and Bigfloats.
+		{value: cbor.DecimalFraction{Exponent: -2, Mantissa: big.NewInt(27315)}, encoded: "c48221196ab3", expected: "273.15"},
+		{value: cbor.BigFloat{Exponent: -1, Mantissa: big.NewInt(3)}, encoded: "c5822003", expected: "1.5"},
+		{value: cbor.DecimalFraction{Exponent: 3, Mantissa: big.NewInt(-12)}, encoded: "c482032b", expected: "-12000"},
+		{value: cbor.DecimalFraction{Exponent: -20, Mantissa: newBigInt("100000000000000000000")}, encoded: "c48233c249056bc75e2d63100000", expected: "1"},
+	}
+	for _, test := range tests {
+		t.Run(test.encoded, func(t *testing.T) {
+			encoded, err := cbor.Marshal(test.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(encoded) != test.encoded {
+				t.Errorf("%s != %s", hex.EncodeToString(encoded), test.encoded)
+			}
+			decoded, err := cbor.Unmarshal(encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var f *big.Float
+			switch v := decoded.(type) {
+			case cbor.DecimalFraction:
+				f = v.BigFloat()
+			case cbor.BigFloat:
+				f = v.BigFloat()
+			default:
+				t.Fatalf("%v (%T) is not a fraction", decoded, decoded)
+			}
+			if s := f.Text('f', -1); s != test.expected {
+				t.Errorf("%s != %s", s, test.expected)
+			}
+			var to big.Float
+			if err := cbor.UnmarshalTo(encoded, &to); err != nil {
+				t.Fatal(err)
+			}
+			if to.Cmp(f) != 0 {
+				t.Errorf("%v != %v", &to, f)
+			}
+		})
+	}
+
+	t.Run("struct", func(t *testing.T) {
+		from := FractionStruct{
+			Price: cbor.DecimalFraction{Exponent: -2, Mantissa: big.NewInt(1999)},
+			Ratio: cbor.BigFloat{Exponent: -3, Mantissa: big.NewInt(5)},
+		}
+		encoded, err := cbor.Marshal(from)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var to FractionStruct
+		if err := cbor.UnmarshalTo(encoded, &to); err != nil {
+			t.Fatal(err)
+		}
+		if to.Price.Exponent != from.Price.Exponent || to.Price.Mantissa.Cmp(from.Price.Mantissa) != 0 ||
+			to.Ratio.Exponent != from.Ratio.Exponent || to.Ratio.Mantissa.Cmp(from.Ratio.Mantissa) != 0 {
+			t.Errorf("%+v != %+v", to, from)
+		}
+	})
+
+	t.Run("NewBigFloat", func(t *testing.T) {
+		for _, s := range []string{"0", "1.5", "-0.375", "1024", "3.0517578125e-05"} {
+			f, _, err := big.ParseFloat(s, 10, 64, big.ToNearestEven)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := cbor.NewBigFloat(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b.BigFloat().Cmp(f) != 0 {
+				t.Errorf("%v != %v", b.BigFloat(), f)
+			}
+		}
+		if _, err := cbor.NewBigFloat(new(big.Float).SetInf(false)); err == nil {
+			t.Error("Expected error for infinity")
+		}
+	})
+
+	t.Run("huge exponent", func(t *testing.T) {
+		tests := []struct {
+			encoded  string
+			expected string
+		}{
+			// 4([1000000000, 1])
+			{encoded: "c4821a3b9aca0001", expected: "+Inf"},
+			// 4([-1000000001, 1])
+			{encoded: "c4823a3b9aca0001", expected: "0"},
+			// 4([-9223372036854775808, 0])
+			{encoded: "c4823b7fffffffffffffff00", expected: "0"},
+			// 5([9223372036854775807, 1])
+			{encoded: "c5821b7fffffffffffffff01", expected: "+Inf"},
+			// 5([9223372036854775807, -1])
+			{encoded: "c5821b7fffffffffffffff20", expected: "-Inf"},
+			// 5([-9223372036854775808, 1])
+			{encoded: "c5823b7fffffffffffffff01", expected: "0"},
+			// 5([2147483647, 1])
+			{encoded: "c5821a7fffffff01", expected: "+Inf"},
+		}
+		for _, test := range tests {
+			encoded, err := hex.DecodeString(test.encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var to big.Float
+			if err := cbor.UnmarshalTo(encoded, &to); err != nil {
+				t.Fatal(err)
+			}
+			if s := to.Text('g', 10); s != test.expected {
+				t.Errorf("%s != %s", s, test.expected)
+			}
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		for _, test := range []string{"c401", "c48101", "c58261616161"} {
+			encoded, err := hex.DecodeString(test)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := cbor.Unmarshal(encoded); !errors.Is(err, cbor.ErrDecode) {
+				t.Errorf("Expected decode error for %s: %v", test, err)
+			}
+		}
+	})
+}
@@ -16,10 +16,20 @@ package cbortest
 
 import (
 	"fmt"
+	"math/big"
 	"reflect"
 	"strings"
 )
 
+// newBigInt returns a big integer of the specified decimal string.
+func newBigInt(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic(s)
+	}
+	return n
+}
+
 func deepEqual(fromObj any, toObj any) error {
 	tostring := func(v any) string {
 		if reflect.TypeOf(v).Kind() == reflect.Pointer {
@@ -0,0 +1,153 @@
+// Copyright (C) 2022 The go-cbor Authors All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cbortest
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cybergarage/go-cbor/cbor"
+)
+
+// MarshalerID is encoded as a text string such as "ID-1".
+type MarshalerID uint32
+
+func (id MarshalerID) MarshalCBOR() ([]byte, error) {
+	return cbor.Marshal(fmt.Sprintf("ID-%d", id))
+}
+
+func (id *MarshalerID) UnmarshalCBOR(data []byte) error {
+	v, err := cbor.Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	s, ok := v.(string)
+	if !ok || !strings.HasPrefix(s, "ID-") {
+		return fmt.Errorf("invalid id: %v", v)
+	}
+	_, err = fmt.Sscanf(s, "ID-%d", id)
+	return err
+}
+
+// MarshalerItem returns the specified bytes as its encoded data item.
+type MarshalerItem []byte
+
+func (item MarshalerItem) MarshalCBOR() ([]byte, error) {
+	return item, nil
+}
+
+type MarshalerStruct struct {
+	ID   MarshalerID
+	IDs  []MarshalerID
+	Refs map[string]MarshalerID
+	Ptr  *MarshalerID
+}
+
+func TestMarshaler(t *testing.T) {
+	t.Run("encode", func(t *testing.T) {
+		encoded, err := cbor.Marshal(MarshalerID(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := "6449442d31"
+		if hex.EncodeToString(encoded) != expected {
+			t.Errorf("%s != %s", hex.EncodeToString(encoded), expected)
+		}
+	})
+
+	t.Run("unmarshal", func(t *testing.T) {
+		encoded, err := cbor.Marshal(MarshalerID(123))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var id MarshalerID
+		if err := cbor.UnmarshalTo(encoded, &id); err != nil {
+			t.Fatal(err)
+		}
+		if id != 123 {
+			t.Errorf("%d != %d", id, 123)
+		}
+	})
+
+	t.Run("struct", func(t *testing.T) {
+		ptr := MarshalerID(4)
+		from := MarshalerStruct{
+			ID:   1,
+			IDs:  []MarshalerID{2, 3},
+			Refs: map[string]MarshalerID{"a": 5},
+			Ptr:  &ptr,
+		}
+		encoded, err := cbor.Marshal(from)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := cbor.Unmarshal(encoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v := decoded.(map[any]any)["ID"]; v != "ID-1" {
+			t.Errorf("%v != %v", v, "ID-1")
+		}
+		var to MarshalerStruct
+		if err := cbor.UnmarshalTo(encoded, &to); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(from, to) {
+			t.Errorf("%+v != %+v", to, from)
+		}
+	})
+
+	t.Run("item", func(t *testing.T) {
+		tests := []struct {
+			item     string
+			expected error
+		}{
+			{item: "01", expected: nil},
+			{item: "820102", expected: nil},
+			{item: "", expected: cbor.ErrEncode},
+			{item: "18", expected: cbor.ErrEncode},
+			{item: "8201", expected: cbor.ErrEncode},
+			{item: "0101", expected: cbor.ErrEncode},
+			{item: "ff", expected: cbor.ErrEncode},
+		}
+		for _, test := range tests {
+			t.Run(test.item, func(t *testing.T) {
+				item, err := hex.DecodeString(test.item)
+				if err != nil {
+					t.Fatal(err)
+				}
+				_, err = cbor.Marshal(map[string]any{"a": MarshalerItem(item)})
+				if !errors.Is(err, test.expected) {
+					t.Errorf("%v != %v", err, test.expected)
+				}
+			})
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		encoded, err := cbor.Marshal("invalid")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var id MarshalerID
+		if err := cbor.UnmarshalTo(encoded, &id); err == nil {
+			t.Error("Expected error from UnmarshalCBOR")
+		}
+	})
+}
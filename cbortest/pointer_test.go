@@ -0,0 +1,156 @@
+// Copyright (C) 2022 The go-cbor Authors All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cbortest
+
+import (
+	"bytes"
+	"encoding/hex"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cybergarage/go-cbor/cbor"
+)
+
+type PointerChild struct {
+	Name string
+}
+
+type PointerStruct struct {
+	Int    *int
+	Str    *string
+	Ints   *[]int
+	IntPtr **int
+	Child  *PointerChild
+	Time   *time.Time
+	ID     *MarshalerID
+}
+
+func TestPointerEncoder(t *testing.T) {
+	n := 1
+	s := "a"
+	np := &n
+	var nilInt *int
+	var nilID *MarshalerID
+
+	tests := []struct {
+		value    any
+		expected string
+	}{
+		{value: &n, expected: "1b0000000000000001"},
+		{value: &s, expected: "6161"},
+		{value: &np, expected: "1b0000000000000001"},
+		{value: nilInt, expected: "f6"},
+		{value: nilID, expected: "f6"},
+		{value: []*int{&n, nil}, expected: "821b0000000000000001f6"},
+		{value: &PointerChild{Name: "a"}, expected: "a1644e616d656161"},
+	}
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			encoded, err := cbor.Marshal(test.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(encoded) != test.expected {
+				t.Errorf("%s != %s", hex.EncodeToString(encoded), test.expected)
+			}
+		})
+	}
+}
+
+func TestPointerUnmarshal(t *testing.T) {
+	t.Run("struct", func(t *testing.T) {
+		n := 1
+		s := "a"
+		np := &n
+		tm := time.Date(2013, 3, 21, 20, 4, 0, 0, time.UTC)
+		id := MarshalerID(2)
+		tests := []PointerStruct{
+			{},
+			{
+				Int:    &n,
+				Str:    &s,
+				Ints:   &[]int{1, 2},
+				IntPtr: &np,
+				Child:  &PointerChild{Name: "b"},
+				Time:   &tm,
+				ID:     &id,
+			},
+		}
+		for _, from := range tests {
+			encoded, err := cbor.Marshal(from)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var to PointerStruct
+			if err := cbor.UnmarshalTo(encoded, &to); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(from, to) {
+				t.Errorf("%+v != %+v", to, from)
+			}
+		}
+	})
+
+	t.Run("null", func(t *testing.T) {
+		n := 1
+		to := PointerStruct{Int: &n, Child: &PointerChild{Name: "b"}}
+		// {"Int": null, "Child": null}
+		encoded, err := hex.DecodeString("a263496e74f6654368696c64f6")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := cbor.UnmarshalTo(encoded, &to); err != nil {
+			t.Fatal(err)
+		}
+		if to.Int != nil || to.Child != nil {
+			t.Errorf("%+v is not nil", to)
+		}
+	})
+
+	t.Run("multi-level", func(t *testing.T) {
+		var to **int
+		if err := cbor.UnmarshalTo([]byte{0x02}, &to); err != nil {
+			t.Fatal(err)
+		}
+		if to == nil || *to == nil || **to != 2 {
+			t.Errorf("%v != %d", to, 2)
+		}
+		if err := cbor.UnmarshalTo([]byte{0xf6}, &to); err != nil {
+			t.Fatal(err)
+		}
+		if to != nil {
+			t.Errorf("%v is not nil", to)
+		}
+	})
+
+	t.Run("map", func(t *testing.T) {
+		n := 1
+		from := map[string]*int{"a": &n, "b": nil}
+		var w bytes.Buffer
+		encoder := cbor.NewEncoder(&w)
+		encoder.SetMapSortEnabled(true)
+		if err := encoder.Encode(from); err != nil {
+			t.Fatal(err)
+		}
+		var to map[string]*int
+		if err := cbor.UnmarshalTo(w.Bytes(), &to); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(from, to) {
+			t.Errorf("%v != %v", to, from)
+		}
+	})
+}
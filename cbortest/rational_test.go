@@ -0,0 +1,104 @@
+// Copyright (C) 2022 The go-cbor Authors All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cbortest
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/cybergarage/go-cbor/cbor"
+)
+
+type RationalStruct struct {
+	Ratio    *big.Rat
+	RatioVal big.Rat
+}
+
+type RationalFloatStruct struct {
+	Ratio    float64
+	RatioVal float32
+}
+
+func TestRational(t *testing.T) {
+	tests := []struct {
+		value    *big.Rat
+		expected string
+	}{
+		{value: big.NewRat(1, 3), expected: "d81e820103"},
+		{value: big.NewRat(-2, 4), expected: "d81e822002"},
+		{value: big.NewRat(0, 1), expected: "d81e820001"},
+		{value: new(big.Rat).SetFrac(newBigInt("18446744073709551616"), big.NewInt(7)), expected: "d81e82c24901000000000000000007"},
+	}
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			encoded, err := cbor.Marshal(test.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(encoded) != test.expected {
+				t.Errorf("%s != %s", hex.EncodeToString(encoded), test.expected)
+			}
+			decoded, err := cbor.Unmarshal(encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r, ok := decoded.(*big.Rat); !ok || r.Cmp(test.value) != 0 {
+				t.Errorf("%v != %v", decoded, test.value)
+			}
+		})
+	}
+
+	t.Run("struct", func(t *testing.T) {
+		from := RationalStruct{
+			Ratio:    big.NewRat(22, 7),
+			RatioVal: *big.NewRat(-1, 8),
+		}
+		encoded, err := cbor.Marshal(from)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var to RationalStruct
+		if err := cbor.UnmarshalTo(encoded, &to); err != nil {
+			t.Fatal(err)
+		}
+		if to.Ratio.Cmp(from.Ratio) != 0 || to.RatioVal.Cmp(&from.RatioVal) != 0 {
+			t.Errorf("%+v != %+v", to, from)
+		}
+
+		var toFloat RationalFloatStruct
+		if err := cbor.UnmarshalTo(encoded, &toFloat); err != nil {
+			t.Fatal(err)
+		}
+		expected := RationalFloatStruct{Ratio: 22.0 / 7.0, RatioVal: -0.125}
+		if toFloat != expected {
+			t.Errorf("%+v != %+v", toFloat, expected)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		// 30([1, 0]), 30([1, -1]), 30(1)
+		for _, test := range []string{"d81e820100", "d81e820120", "d81e01"} {
+			encoded, err := hex.DecodeString(test)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := cbor.Unmarshal(encoded); !errors.Is(err, cbor.ErrDecode) {
+				t.Errorf("Expected decode error for %s: %v", test, err)
+			}
+		}
+	})
+}
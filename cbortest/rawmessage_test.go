@@ -0,0 +1,138 @@
+// Copyright (C) 2022 The go-cbor Authors All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cbortest
+
+import (
+	"bytes"
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	"github.com/cybergarage/go-cbor/cbor"
+)
+
+type RawMessageEnvelope struct {
+	Type    string
+	Payload cbor.RawMessage
+}
+
+type RawMessagePayload struct {
+	Name  string
+	Value int
+}
+
+func TestRawMessage(t *testing.T) {
+	t.Run("envelope", func(t *testing.T) {
+		payload := RawMessagePayload{Name: "a", Value: 1}
+		encodedPayload, err := cbor.Marshal(payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		from := RawMessageEnvelope{Type: "payload", Payload: encodedPayload}
+		encoded, err := cbor.Marshal(from)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var to RawMessageEnvelope
+		if err := cbor.UnmarshalTo(encoded, &to); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(from, to) {
+			t.Errorf("%+v != %+v", to, from)
+		}
+
+		var toPayload RawMessagePayload
+		if err := cbor.UnmarshalTo(to.Payload, &toPayload); err != nil {
+			t.Fatal(err)
+		}
+		if toPayload != payload {
+			t.Errorf("%+v != %+v", toPayload, payload)
+		}
+	})
+
+	t.Run("verbatim", func(t *testing.T) {
+		// Non-preferred and indefinite-length encodings are kept as they are.
+		tests := []string{
+			"1b0000000000000001",
+			"5f42010243030405ff",
+			"bf61610161629f0203ffff",
+			"d82076687474703a2f2f7777772e6578616d706c652e636f6d",
+		}
+		for _, test := range tests {
+			t.Run(test, func(t *testing.T) {
+				item, err := hex.DecodeString(test)
+				if err != nil {
+					t.Fatal(err)
+				}
+				encoded := append([]byte{0x82}, item...)
+				encoded = append(encoded, item...)
+
+				var to []cbor.RawMessage
+				if err := cbor.UnmarshalTo(encoded, &to); err != nil {
+					t.Fatal(err)
+				}
+				if len(to) != 2 || !bytes.Equal(to[0], item) || !bytes.Equal(to[1], item) {
+					t.Fatalf("%x != %s", to, test)
+				}
+
+				reencoded, err := cbor.Marshal(to)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(reencoded, encoded) {
+					t.Errorf("%s != %s", hex.EncodeToString(reencoded), hex.EncodeToString(encoded))
+				}
+			})
+		}
+	})
+
+	t.Run("map", func(t *testing.T) {
+		encoded, err := cbor.Marshal(map[string]any{"a": []int8{1, 2}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var to map[string]cbor.RawMessage
+		if err := cbor.UnmarshalTo(encoded, &to); err != nil {
+			t.Fatal(err)
+		}
+		if expected := "820102"; hex.EncodeToString(to["a"]) != expected {
+			t.Errorf("%s != %s", hex.EncodeToString(to["a"]), expected)
+		}
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		for _, payload := range []cbor.RawMessage{nil, {}} {
+			encoded, err := cbor.Marshal(RawMessageEnvelope{Type: "empty", Payload: payload})
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := cbor.Unmarshal(encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v, ok := decoded.(map[any]any)["Payload"]; !ok || v != nil {
+				t.Errorf("%v is not null", v)
+			}
+		}
+		encoded, err := cbor.Marshal(cbor.RawMessage{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(encoded) != "f6" {
+			t.Errorf("%s != %s", hex.EncodeToString(encoded), "f6")
+		}
+	})
+}
@@ -0,0 +1,142 @@
+// Copyright (C) 2022 The go-cbor Authors All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cbortest
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/cybergarage/go-cbor/cbor"
+)
+
+func TestSimpleValue(t *testing.T) {
+	tests := []struct {
+		encoded  string
+		expected any
+	}{
+		// RFC 8949: Appendix A. Examples of Encoded CBOR Data Items.
+		{encoded: "f7", expected: cbor.Undefined{}},
+		{encoded: "f0", expected: cbor.SimpleValue(16)},
+		{encoded: "f8ff", expected: cbor.SimpleValue(255)},
+		{encoded: "e0", expected: cbor.SimpleValue(0)},
+		{encoded: "f820", expected: cbor.SimpleValue(32)},
+		{encoded: "82f7f3", expected: []any{cbor.Undefined{}, cbor.SimpleValue(19)}},
+	}
+	for _, test := range tests {
+		t.Run(test.encoded, func(t *testing.T) {
+			encoded, err := hex.DecodeString(test.encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := cbor.Unmarshal(encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(decoded, test.expected) {
+				t.Errorf("%v (%T) != %v (%T)", decoded, decoded, test.expected, test.expected)
+			}
+			reencoded, err := cbor.Marshal(decoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(reencoded, encoded) {
+				t.Errorf("%s != %s", hex.EncodeToString(reencoded), test.encoded)
+			}
+		})
+	}
+
+	t.Run("unmarshal", func(t *testing.T) {
+		encoded, err := hex.DecodeString("f8ff")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var v cbor.SimpleValue
+		if err := cbor.UnmarshalTo(encoded, &v); err != nil {
+			t.Fatal(err)
+		}
+		if v != 255 {
+			t.Errorf("%d != %d", v, 255)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		for _, test := range []string{"f800", "f818", "f81f"} {
+			encoded, err := hex.DecodeString(test)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := cbor.Unmarshal(encoded); !errors.Is(err, cbor.ErrDecode) {
+				t.Errorf("Expected decode error for %s: %v", test, err)
+			}
+		}
+		for _, v := range []cbor.SimpleValue{24, 31} {
+			if _, err := cbor.Marshal(v); err == nil {
+				t.Errorf("Expected encode error for %d", v)
+			}
+		}
+	})
+}
+
+func TestUndefinedMode(t *testing.T) {
+	type UndefinedStruct struct {
+		Name  string
+		Value *int
+		Undef cbor.Undefined
+	}
+
+	// {"Name": undefined, "Value": undefined, "Undef": undefined}
+	encoded, err := cbor.Marshal(map[string]any{"Name": cbor.Undefined{}, "Value": cbor.Undefined{}, "Undef": cbor.Undefined{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("zero", func(t *testing.T) {
+		n := 1
+		to := UndefinedStruct{Name: "name", Value: &n}
+		if err := cbor.UnmarshalTo(encoded, &to); err != nil {
+			t.Fatal(err)
+		}
+		if to.Name != "" || to.Value != nil {
+			t.Errorf("%+v is not zero", to)
+		}
+
+		i := 1
+		if err := cbor.UnmarshalTo([]byte{0xf7}, &i); err != nil {
+			t.Fatal(err)
+		}
+		if i != 0 {
+			t.Errorf("%d != %d", i, 0)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		decoder := cbor.NewDecoder(bytes.NewReader(encoded))
+		decoder.SetUndefinedMode(cbor.UndefinedError)
+		var to UndefinedStruct
+		if err := decoder.Unmarshal(&to); !errors.Is(err, cbor.ErrUnmarshal) {
+			t.Errorf("Expected unmarshal error: %v", err)
+		}
+
+		decoder = cbor.NewDecoder(bytes.NewReader([]byte{0xf7}))
+		decoder.SetUndefinedMode(cbor.UndefinedError)
+		var undef cbor.Undefined
+		if err := decoder.Unmarshal(&undef); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,173 @@
+// Copyright (C) 2022 The go-cbor Authors All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cbortest
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/cybergarage/go-cbor/cbor"
+)
+
+type EmbeddedBase struct {
+	Name string
+	Kind string
+}
+
+type EmbeddedTagged struct {
+	Label string `cbor:"label"`
+}
+
+type embeddedUnexported struct {
+	Note string
+}
+
+type EmbeddedParent struct {
+	EmbeddedBase
+	*EmbeddedTagged
+	embeddedUnexported
+
+	Extra  string
+	secret string
+}
+
+type EmbeddedShadow struct {
+	EmbeddedBase
+
+	Name string
+}
+
+type EmbeddedConflict struct {
+	EmbeddedBase
+	EmbeddedOther
+}
+
+type EmbeddedOther struct {
+	Kind  string
+	Other string
+}
+
+type EmbeddedPrivatePointer struct {
+	*embeddedUnexported
+}
+
+func TestEmbeddedStructEncoding(t *testing.T) {
+	tests := []struct {
+		value    any
+		expected string
+	}{
+		{
+			// {"Kind": "b", "Name": "a", "Note": "d", "Extra": "e", "label": "c"}
+			value: EmbeddedParent{
+				EmbeddedBase:       EmbeddedBase{Name: "a", Kind: "b"},
+				EmbeddedTagged:     &EmbeddedTagged{Label: "c"},
+				embeddedUnexported: embeddedUnexported{Note: "d"},
+				Extra:              "e",
+				secret:             "s",
+			},
+			expected: "a5644b696e646162644e616d656161644e6f746561646545787472616165656c6162656c6163",
+		},
+		{
+			// {"Kind": "b", "Name": "a", "Note": "", "Extra": ""}
+			value:    EmbeddedParent{EmbeddedBase: EmbeddedBase{Name: "a", Kind: "b"}},
+			expected: "a4644b696e646162644e616d656161644e6f74656065457874726160",
+		},
+		{
+			// {"Kind": "b", "Name": "c"}
+			value:    EmbeddedShadow{EmbeddedBase: EmbeddedBase{Name: "a", Kind: "b"}, Name: "c"},
+			expected: "a2644b696e646162644e616d656163",
+		},
+		{
+			// {"Name": "a", "Other": "c"}
+			value:    EmbeddedConflict{EmbeddedBase{Name: "a", Kind: "b"}, EmbeddedOther{Kind: "b", Other: "c"}},
+			expected: "a2644e616d656161654f746865726163",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			var w bytes.Buffer
+			encoder := cbor.NewEncoder(&w)
+			encoder.SetMapSortMode(cbor.MapSortBytewise)
+			if err := encoder.Encode(test.value); err != nil {
+				t.Fatal(err)
+			}
+			if encoded := hex.EncodeToString(w.Bytes()); encoded != test.expected {
+				t.Errorf("%s != %s", encoded, test.expected)
+			}
+		})
+	}
+}
+
+func TestEmbeddedStructUnmarshal(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		from := EmbeddedParent{
+			EmbeddedBase:       EmbeddedBase{Name: "a", Kind: "b"},
+			EmbeddedTagged:     &EmbeddedTagged{Label: "c"},
+			embeddedUnexported: embeddedUnexported{Note: "d"},
+			Extra:              "e",
+			secret:             "s",
+		}
+		encoded, err := cbor.Marshal(from)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var to EmbeddedParent
+		if err := cbor.UnmarshalTo(encoded, &to); err != nil {
+			t.Fatal(err)
+		}
+		expected := from
+		expected.secret = ""
+		if !reflect.DeepEqual(to, expected) {
+			t.Errorf("%+v != %+v", to, expected)
+		}
+	})
+
+	t.Run("shadow", func(t *testing.T) {
+		from := EmbeddedShadow{EmbeddedBase: EmbeddedBase{Kind: "b"}, Name: "c"}
+		encoded, err := cbor.Marshal(from)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var to EmbeddedShadow
+		if err := cbor.UnmarshalTo(encoded, &to); err != nil {
+			t.Fatal(err)
+		}
+		if to != from {
+			t.Errorf("%+v != %+v", to, from)
+		}
+	})
+
+	t.Run("unexported pointer", func(t *testing.T) {
+		// {"Note": "d"}
+		encoded, err := hex.DecodeString("a1644e6f74656164")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var to EmbeddedPrivatePointer
+		if err := cbor.UnmarshalTo(encoded, &to); !errors.Is(err, cbor.ErrUnmarshal) {
+			t.Errorf("Expected unmarshal error for a nil embedded pointer to an unexported struct: %v", err)
+		}
+		to = EmbeddedPrivatePointer{&embeddedUnexported{}}
+		if err := cbor.UnmarshalTo(encoded, &to); err != nil {
+			t.Fatal(err)
+		}
+		if to.Note != "d" {
+			t.Errorf("%s != %s", to.Note, "d")
+		}
+	})
+}
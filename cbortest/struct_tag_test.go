@@ -0,0 +1,218 @@
+// Copyright (C) 2022 The go-cbor Authors All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cbortest
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/cybergarage/go-cbor/cbor"
+)
+
+type TaggedStruct struct {
+	Name    string `cbor:"name"`
+	Age     int    `cbor:"age,omitempty"`
+	Secret  string `cbor:"-"`
+	Comment string `cbor:",omitempty"`
+	Extra   string
+}
+
+type ToArrayStruct struct {
+	_      struct{} `cbor:",toarray"`
+	Name   string
+	ID     MarshalerID
+	Active bool
+	Tags   []string
+	Opt    *string `cbor:",omitempty"`
+}
+
+type KeyAsIntStruct struct {
+	Alg  int8   `cbor:"1,keyasint"`
+	Kid  string `cbor:"4,keyasint,omitempty"`
+	Neg  string `cbor:"-1,keyasint"`
+	Name string `cbor:"name"`
+	Big  int8   `cbor:"1000,keyasint"`
+}
+
+func TestStructTagEncoding(t *testing.T) {
+	tests := []struct {
+		value    TaggedStruct
+		expected string
+	}{
+		{
+			value:    TaggedStruct{Name: "a", Age: 0, Secret: "s", Comment: "", Extra: ""},
+			expected: "a265457874726160646e616d656161",
+		},
+		{
+			value:    TaggedStruct{Name: "a", Age: 1, Secret: "s", Comment: "c", Extra: "e"},
+			expected: "a467436f6d6d656e7461636545787472616165636167651b0000000000000001646e616d656161",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			var w bytes.Buffer
+			encoder := cbor.NewEncoder(&w)
+			encoder.SetMapSortEnabled(true)
+			if err := encoder.Encode(test.value); err != nil {
+				t.Fatal(err)
+			}
+			if encoded := hex.EncodeToString(w.Bytes()); encoded != test.expected {
+				t.Errorf("%s != %s", encoded, test.expected)
+			}
+		})
+	}
+}
+
+func TestStructTagUnmarshal(t *testing.T) {
+	from := TaggedStruct{Name: "alice", Age: 20, Secret: "secret", Comment: "hello", Extra: "extra"}
+	encoded, err := cbor.Marshal(from)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := cbor.Unmarshal(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decodedMap, ok := decoded.(map[any]any)
+	if !ok {
+		t.Fatalf("Expected map result for struct, got %T", decoded)
+	}
+	for _, key := range []string{"name", "age", "Comment", "Extra"} {
+		if _, ok := decodedMap[key]; !ok {
+			t.Errorf("key (%s) is not found in %v", key, decodedMap)
+		}
+	}
+	if _, ok := decodedMap["Secret"]; ok {
+		t.Errorf("skipped key (Secret) is found in %v", decodedMap)
+	}
+
+	var to TaggedStruct
+	if err := cbor.UnmarshalTo(encoded, &to); err != nil {
+		t.Fatal(err)
+	}
+	expected := from
+	expected.Secret = ""
+	if to != expected {
+		t.Errorf("%+v != %+v", to, expected)
+	}
+
+	// Go field names of renamed fields are not matched.
+	encoded, err = cbor.Marshal(map[string]string{"Name": "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cbor.UnmarshalTo(encoded, &to); err == nil {
+		t.Error("Expected error when unmarshaling a renamed field by its Go name")
+	}
+}
+
+func TestStructTagToArray(t *testing.T) {
+	// ["a", "ID-1", true, ["b"], null]
+	expected := "8561616449442d31f5816162f6"
+	from := ToArrayStruct{Name: "a", ID: 1, Active: true, Tags: []string{"b"}, Opt: nil}
+	encoded, err := cbor.Marshal(from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(encoded) != expected {
+		t.Errorf("%s != %s", hex.EncodeToString(encoded), expected)
+	}
+
+	var to ToArrayStruct
+	if err := cbor.UnmarshalTo(encoded, &to); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(to, from) {
+		t.Errorf("%+v != %+v", to, from)
+	}
+
+	opt := "c"
+	from.Opt = &opt
+	encoded, err = cbor.Marshal([]ToArrayStruct{from})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tos []ToArrayStruct
+	if err := cbor.UnmarshalTo(encoded, &tos); err != nil {
+		t.Fatal(err)
+	}
+	if len(tos) != 1 || !reflect.DeepEqual(tos[0], from) {
+		t.Errorf("%+v != %+v", tos, from)
+	}
+
+	// ["a", "ID-1", true]
+	encoded, err = hex.DecodeString("8361616449442d31f5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cbor.UnmarshalTo(encoded, &to); !errors.Is(err, cbor.ErrUnmarshal) {
+		t.Errorf("Expected unmarshal error for a short array: %v", err)
+	}
+}
+
+func TestStructTagKeyAsInt(t *testing.T) {
+	from := KeyAsIntStruct{Alg: -7, Kid: "a", Neg: "b", Name: "c", Big: 0}
+
+	tests := []struct {
+		mode     cbor.MapSortMode
+		expected string
+	}{
+		{
+			// {1: -7, 4: "a", 1000: 0, -1: "b", "name": "c"}
+			mode:     cbor.MapSortBytewise,
+			expected: "a501260461611903e800206162646e616d656163",
+		},
+		{
+			// {1: -7, 4: "a", -1: "b", 1000: 0, "name": "c"}
+			mode:     cbor.MapSortLengthFirst,
+			expected: "a501260461612061621903e800646e616d656163",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			var w bytes.Buffer
+			encoder := cbor.NewEncoder(&w)
+			encoder.SetMapSortMode(test.mode)
+			if err := encoder.Encode(from); err != nil {
+				t.Fatal(err)
+			}
+			if encoded := hex.EncodeToString(w.Bytes()); encoded != test.expected {
+				t.Errorf("%s != %s", encoded, test.expected)
+			}
+			var to KeyAsIntStruct
+			if err := cbor.UnmarshalTo(w.Bytes(), &to); err != nil {
+				t.Fatal(err)
+			}
+			if to != from {
+				t.Errorf("%+v != %+v", to, from)
+			}
+		})
+	}
+
+	// {"1": -7}
+	encoded, err := hex.DecodeString("a1613126")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var to KeyAsIntStruct
+	if err := cbor.UnmarshalTo(encoded, &to); err == nil {
+		t.Error("Expected error when unmarshaling an integer key field by its text string name")
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright (C) 2022 The go-cbor Authors All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cbortest
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cybergarage/go-cbor/cbor"
+)
+
+// TagPoint is registered as a tag whose content is the struct itself.
+type TagPoint struct {
+	X int8
+	Y int8
+}
+
+// TagVersion is registered as a tag whose content is a text string such as "1.2".
+type TagVersion struct {
+	Major int
+	Minor int
+}
+
+// TagUUID is registered as a tag whose content is the array itself.
+type TagUUID [4]byte
+
+type TagStruct struct {
+	Point   TagPoint
+	Version TagVersion
+	Points  []TagPoint
+}
+
+func newTestTagSet(t *testing.T) *cbor.TagSet {
+	t.Helper()
+	ts := cbor.NewTagSet()
+	if err := ts.Add(40000, TagPoint{}, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	encodeVersion := func(v any) (any, error) {
+		version, ok := v.(TagVersion)
+		if !ok {
+			return nil, fmt.Errorf("invalid version: %v", v)
+		}
+		return fmt.Sprintf("%d.%d", version.Major, version.Minor), nil
+	}
+	decodeVersion := func(content any) (any, error) {
+		s, ok := content.(string)
+		if !ok || !strings.Contains(s, ".") {
+			return nil, fmt.Errorf("invalid version: %v", content)
+		}
+		var version TagVersion
+		_, err := fmt.Sscanf(s, "%d.%d", &version.Major, &version.Minor)
+		return version, err
+	}
+	if err := ts.Add(40001, TagVersion{}, encodeVersion, decodeVersion); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.Add(40003, TagUUID{}, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	return ts
+}
+
+func TestTagSet(t *testing.T) {
+	ts := newTestTagSet(t)
+
+	encode := func(t *testing.T, v any) []byte {
+		t.Helper()
+		var writer bytes.Buffer
+		encoder := cbor.NewEncoder(&writer)
+		encoder.SetMapSortEnabled(true)
+		encoder.SetTagSet(ts)
+		if err := encoder.Encode(v); err != nil {
+			t.Fatal(err)
+		}
+		return writer.Bytes()
+	}
+
+	t.Run("encode", func(t *testing.T) {
+		tests := []struct {
+			value    any
+			expected string
+		}{
+			{value: TagPoint{X: 1, Y: 2}, expected: "d99c40a2615801615902"},
+			{value: &TagPoint{X: 1, Y: 2}, expected: "d99c40a2615801615902"},
+			{value: TagVersion{Major: 1, Minor: 2}, expected: "d99c4163312e32"},
+			{value: TagUUID{1, 2, 3, 4}, expected: "d99c438401020304"},
+		}
+		for _, test := range tests {
+			t.Run(test.expected, func(t *testing.T) {
+				if encoded := hex.EncodeToString(encode(t, test.value)); encoded != test.expected {
+					t.Errorf("%s != %s", encoded, test.expected)
+				}
+			})
+		}
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		for _, value := range []any{TagPoint{X: 1, Y: 2}, TagVersion{Major: 1, Minor: 2}, TagUUID{1, 2, 3, 4}} {
+			decoder := cbor.NewDecoder(bytes.NewReader(encode(t, value)))
+			decoder.SetTagSet(ts)
+			decoded, err := decoder.Decode()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if decoded != value {
+				t.Errorf("%v (%T) != %v (%T)", decoded, decoded, value, value)
+			}
+		}
+	})
+
+	t.Run("unmarshal", func(t *testing.T) {
+		from := TagStruct{
+			Point:   TagPoint{X: 1, Y: 2},
+			Version: TagVersion{Major: 3, Minor: 4},
+			Points:  []TagPoint{{X: 5, Y: 6}},
+		}
+		encoded := encode(t, from)
+
+		decoder := cbor.NewDecoder(bytes.NewReader(encoded))
+		decoder.SetTagSet(ts)
+		var to TagStruct
+		if err := decoder.Unmarshal(&to); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(from, to) {
+			t.Errorf("%+v != %+v", to, from)
+		}
+
+		decoder = cbor.NewDecoder(bytes.NewReader(encode(t, from.Version)))
+		decoder.SetTagSet(ts)
+		var version TagVersion
+		if err := decoder.Unmarshal(&version); err != nil {
+			t.Fatal(err)
+		}
+		if version != from.Version {
+			t.Errorf("%+v != %+v", version, from.Version)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		if err := ts.Add(40000, struct{}{}, nil, nil); !errors.Is(err, cbor.ErrNotSupported) {
+			t.Errorf("Expected error for a registered tag number: %v", err)
+		}
+		if err := ts.Add(40002, TagPoint{}, nil, nil); !errors.Is(err, cbor.ErrNotSupported) {
+			t.Errorf("Expected error for a registered type: %v", err)
+		}
+		if err := ts.Add(1, struct{}{}, nil, nil); !errors.Is(err, cbor.ErrNotSupported) {
+			t.Errorf("Expected error for a reserved tag number: %v", err)
+		}
+
+		// 40001(1)
+		encoded, err := hex.DecodeString("d99c4101")
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoder := cbor.NewDecoder(bytes.NewReader(encoded))
+		decoder.SetTagSet(ts)
+		if _, err := decoder.Decode(); err == nil {
+			t.Error("Expected error from the decode function")
+		}
+
+		ts.Remove(40001)
+		decoder = cbor.NewDecoder(bytes.NewReader(encoded))
+		decoder.SetTagSet(ts)
+		decoded, err := decoder.Decode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := (cbor.Tag{Number: 40001, Content: int8(1)}); decoded != expected {
+			t.Errorf("%v != %v", decoded, expected)
+		}
+	})
+}
+
+func TestUnknownTag(t *testing.T) {
+	tests := []struct {
+		encoded  string
+		expected cbor.Tag
+	}{
+		// RFC 8949: Appendix A. Examples of Encoded CBOR Data Items.
+		{encoded: "d74401020304", expected: cbor.Tag{Number: 23, Content: []byte{0x01, 0x02, 0x03, 0x04}}},
+		{encoded: "d818456449455446", expected: cbor.Tag{Number: 24, Content: []byte("dIETF")}},
+		{encoded: "d82076687474703a2f2f7777772e6578616d706c652e636f6d", expected: cbor.Tag{Number: 32, Content: "http://www.example.com"}},
+		{encoded: "db0000000100000000d82001", expected: cbor.Tag{Number: 4294967296, Content: cbor.Tag{Number: 32, Content: int8(1)}}},
+	}
+	for _, test := range tests {
+		t.Run(test.encoded, func(t *testing.T) {
+			encoded, err := hex.DecodeString(test.encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := cbor.Unmarshal(encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(decoded, test.expected) {
+				t.Errorf("%v != %v", decoded, test.expected)
+			}
+
+			var writer bytes.Buffer
+			encoder := cbor.NewEncoder(&writer)
+			encoder.SetShortestIntEnabled(true)
+			if err := encoder.Encode(decoded); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(writer.Bytes(), encoded) {
+				t.Errorf("%s != %s", hex.EncodeToString(writer.Bytes()), test.encoded)
+			}
+
+			var rawTag cbor.RawTag
+			if err := cbor.UnmarshalTo(encoded, &rawTag); err != nil {
+				t.Fatal(err)
+			}
+			if rawTag.Number != test.expected.Number {
+				t.Errorf("%d != %d", rawTag.Number, test.expected.Number)
+			}
+			reencoded, err := cbor.Marshal(rawTag)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(reencoded, encoded) {
+				t.Errorf("%s != %s", hex.EncodeToString(reencoded), test.encoded)
+			}
+
+			var tag cbor.Tag
+			if err := cbor.UnmarshalTo(encoded, &tag); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tag, test.expected) {
+				t.Errorf("%v != %v", tag, test.expected)
+			}
+		})
+	}
+
+	t.Run("struct", func(t *testing.T) {
+		type RawTagStruct struct {
+			Time cbor.RawTag
+			URI  cbor.Tag
+		}
+		from := RawTagStruct{
+			Time: cbor.RawTag{Number: 1, Content: []byte{0x1a, 0x51, 0x4b, 0x67, 0xb0}},
+			URI:  cbor.Tag{Number: 32, Content: "http://www.example.com"},
+		}
+		encoded, err := cbor.Marshal(from)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var to RawTagStruct
+		if err := cbor.UnmarshalTo(encoded, &to); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(from, to) {
+			t.Errorf("%+v != %+v", to, from)
+		}
+	})
+}
@@ -192,4 +192,26 @@ func TestUnmarshalTo(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("map_pointer", func(t *testing.T) {
+		from := map[string]int{"one": 1, "two": 2}
+		encBytes, err := cbor.Marshal(from)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var nilMap map[string]int
+		for _, to := range []*map[string]int{&nilMap, {}, {"three": 3}} {
+			if err := cbor.UnmarshalTo(encBytes, to); err != nil {
+				t.Fatal(err)
+			}
+			for k, v := range from {
+				if (*to)[k] != v {
+					t.Errorf("%v != %v", *to, from)
+				}
+			}
+		}
+		if nilMap == nil {
+			t.Error("nil map is not allocated")
+		}
+	})
 }